@@ -0,0 +1,398 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ExtraKeyHit 命中缓存时返回消息的 Extra 与回调的 Extra 中都带有该 key，值为 true
+const ExtraKeyHit = "cache_hit"
+
+// IsHit 消息（或流式分片）是否来自缓存回放
+func IsHit(msg *schema.Message) bool {
+	if msg == nil {
+		return false
+	}
+	hit, _ := msg.Extra[ExtraKeyHit].(bool)
+	return hit
+}
+
+// markHit 返回带命中标记的副本，不修改原消息
+func markHit(msg *schema.Message) *schema.Message {
+	m := *msg
+	m.Extra = make(map[string]any, len(msg.Extra)+1)
+	for k, v := range msg.Extra {
+		m.Extra[k] = v
+	}
+	m.Extra[ExtraKeyHit] = true
+	return &m
+}
+
+// Config 缓存配置
+type Config struct {
+	// Dir 缓存目录，默认 .cache/chatmodel
+	Dir string
+	// TTL 缓存有效期，0 表示永不过期
+	TTL time.Duration
+	// Namespace 参与 key 计算，用于区分不同的模型/服务商（同样的消息发给不同模型结果不同）
+	Namespace string
+}
+
+// ChatModel 带磁盘缓存的 ChatModel 装饰器
+// 以消息、工具、生成参数的规范化哈希为 key；命中时 Generate 直接返回，Stream 按原分片回放
+type ChatModel struct {
+	inner     model.BaseChatModel
+	store     *DiskStore
+	ttl       time.Duration
+	namespace string
+	tools     []*schema.ToolInfo
+}
+
+var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+
+// options 缓存的实现特定选项
+type options struct {
+	Bypass bool
+}
+
+// WithBypass 本次调用跳过缓存（既不读也不写）
+func WithBypass() model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.Bypass = true
+	})
+}
+
+// NewChatModel 用磁盘缓存包装一个 ChatModel
+func NewChatModel(inner model.BaseChatModel, cfg *Config) (*ChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("inner chat model is required")
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	dir := cfg.Dir
+	if dir == "" {
+		dir = ".cache/chatmodel"
+	}
+	store, err := NewDiskStore(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{
+		inner:     inner,
+		store:     store,
+		ttl:       cfg.TTL,
+		namespace: cfg.Namespace,
+	}, nil
+}
+
+// Store 返回底层磁盘存储（用于 Prune 等维护操作）
+func (c *ChatModel) Store() *DiskStore {
+	return c.store
+}
+
+// Generate 命中时直接返回；缓存的是流式分片时拼接成一条消息返回
+func (c *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if model.GetImplSpecificOptions(&options{}, opts...).Bypass {
+		return c.generate(ctx, input, opts...)
+	}
+
+	key, err := c.Key(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if e, _ := c.store.Get(key); e != nil {
+		switch {
+		case e.Message != nil:
+			return c.replay(ctx, input, e.Message), nil
+		case len(e.Chunks) > 0:
+			if msg, err := schema.ConcatMessages(e.Chunks); err == nil {
+				return c.replay(ctx, input, msg), nil
+			}
+		}
+	}
+
+	out, err := c.generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.put(&Entry{Key: key, Message: out})
+	return out, nil
+}
+
+// Stream 命中时按原分片回放；缓存的是 Generate 的结果时作为单个分片回放
+func (c *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if model.GetImplSpecificOptions(&options{}, opts...).Bypass {
+		return c.stream(ctx, input, opts...)
+	}
+
+	key, err := c.Key(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if e, _ := c.store.Get(key); e != nil {
+		switch {
+		case len(e.Chunks) > 0:
+			return c.replayStream(ctx, input, e.Chunks), nil
+		case e.Message != nil:
+			return c.replayStream(ctx, input, []*schema.Message{e.Message}), nil
+		}
+	}
+
+	sr, err := c.stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 一份返回给调用方，一份在后台收集分片；只有完整读到 EOF 才写缓存
+	copies := sr.Copy(2)
+	go func() {
+		defer copies[1].Close()
+		var chunks []*schema.Message
+		for {
+			chunk, err := copies[1].Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return
+			}
+			chunks = append(chunks, chunk)
+		}
+		if len(chunks) > 0 {
+			c.put(&Entry{Key: key, Chunks: chunks})
+		}
+	}()
+	return copies[0], nil
+}
+
+// WithTools 返回绑定了工具的新实例，工具定义会参与缓存 key 计算
+func (c *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tc, ok := c.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("inner chat model does not implement ToolCallingChatModel")
+	}
+	inner, err := tc.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{
+		inner:     inner,
+		store:     c.store,
+		ttl:       c.ttl,
+		namespace: c.namespace,
+		tools:     tools,
+	}, nil
+}
+
+func (c *ChatModel) GetType() string {
+	if typ, ok := components.GetType(c.inner); ok {
+		return "Cached" + typ
+	}
+	return "Cached"
+}
+
+// IsCallbacksEnabled 命中时由缓存自己触发回调（Extra 中带 ExtraKeyHit）；
+// 未命中时内部模型自己触发回调则交给它，否则同样由缓存触发
+func (c *ChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+// generate 调用内部模型，内部模型不触发回调时在这里补上
+func (c *ChatModel) generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if components.IsCallbacksEnabled(c.inner) {
+		return c.inner.Generate(ctx, input, opts...)
+	}
+	ctx = c.onStart(ctx, input, opts, nil)
+	out, err := c.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, &model.CallbackOutput{Message: out})
+	return out, nil
+}
+
+// stream 同 generate，流式输出经回调 handler 转发后返回
+func (c *ChatModel) stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if components.IsCallbacksEnabled(c.inner) {
+		return c.inner.Stream(ctx, input, opts...)
+	}
+	ctx = c.onStart(ctx, input, opts, nil)
+	sr, err := c.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	return c.onEndStream(ctx, sr, nil), nil
+}
+
+func (c *ChatModel) onStart(ctx context.Context, input []*schema.Message, opts []model.Option, extra map[string]any) context.Context {
+	in := &model.CallbackInput{Messages: input, Tools: c.tools, Extra: extra}
+	if len(opts) > 0 {
+		in.Config = callbackConfig(model.GetCommonOptions(&model.Options{}, opts...))
+	}
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	return callbacks.OnStart(ctx, in)
+}
+
+func (c *ChatModel) onEndStream(ctx context.Context, sr *schema.StreamReader[*schema.Message], extra map[string]any) *schema.StreamReader[*schema.Message] {
+	cbStream := schema.StreamReaderWithConvert(sr, func(m *schema.Message) (*model.CallbackOutput, error) {
+		return &model.CallbackOutput{Message: m, Extra: extra}, nil
+	})
+	_, cbStream = callbacks.OnEndWithStreamOutput(ctx, cbStream)
+	return schema.StreamReaderWithConvert(cbStream, func(o *model.CallbackOutput) (*schema.Message, error) {
+		return o.Message, nil
+	})
+}
+
+// callbackConfig 回调中展示的生成参数
+func callbackConfig(o *model.Options) *model.Config {
+	cfg := &model.Config{Stop: o.Stop}
+	if o.Model != nil {
+		cfg.Model = *o.Model
+	}
+	if o.Temperature != nil {
+		cfg.Temperature = *o.Temperature
+	}
+	if o.TopP != nil {
+		cfg.TopP = *o.TopP
+	}
+	if o.MaxTokens != nil {
+		cfg.MaxTokens = *o.MaxTokens
+	}
+	return cfg
+}
+
+func (c *ChatModel) put(e *Entry) {
+	e.CreatedAt = time.Now()
+	if c.ttl > 0 {
+		e.ExpiresAt = e.CreatedAt.Add(c.ttl)
+	}
+	if err := c.store.Put(e); err != nil {
+		log.Printf("[cache] 写入缓存失败: %v", err)
+	}
+}
+
+// replay 命中时补发回调，让 trace / metrics 能看到这次（未真正请求模型的）调用
+// 返回的消息带命中标记，调用方可用 IsHit 区分，避免按缓存的用量重复计费
+func (c *ChatModel) replay(ctx context.Context, input []*schema.Message, msg *schema.Message) *schema.Message {
+	msg = markHit(msg)
+	ctx = c.onStart(ctx, input, nil, map[string]any{ExtraKeyHit: true})
+	_ = callbacks.OnEnd(ctx, &model.CallbackOutput{
+		Message: msg,
+		Extra:   map[string]any{ExtraKeyHit: true},
+	})
+	return msg
+}
+
+func (c *ChatModel) replayStream(ctx context.Context, input []*schema.Message, chunks []*schema.Message) *schema.StreamReader[*schema.Message] {
+	extra := map[string]any{ExtraKeyHit: true}
+	ctx = c.onStart(ctx, input, nil, extra)
+	sr := schema.StreamReaderWithConvert(schema.StreamReaderFromArray(chunks), func(m *schema.Message) (*schema.Message, error) {
+		return markHit(m), nil
+	})
+	return c.onEndStream(ctx, sr, extra)
+}
+
+// keyTool 工具定义的规范化表示（ToolInfo.ParamsOneOf 的字段未导出，需先转成 JSON Schema）
+type keyTool struct {
+	Name   string `json:"name"`
+	Desc   string `json:"desc"`
+	Params any    `json:"params,omitempty"`
+}
+
+// keyPayload 参与哈希的全部内容；encoding/json 对 map 按 key 排序，保证同样的输入得到同样的 key
+type keyPayload struct {
+	Namespace   string             `json:"namespace,omitempty"`
+	Messages    []*schema.Message  `json:"messages"`
+	Tools       []keyTool          `json:"tools,omitempty"`
+	ToolChoice  *schema.ToolChoice `json:"tool_choice,omitempty"`
+	Model       *string            `json:"model,omitempty"`
+	Temperature *float32           `json:"temperature,omitempty"`
+	TopP        *float32           `json:"top_p,omitempty"`
+	MaxTokens   *int               `json:"max_tokens,omitempty"`
+	Stop        []string           `json:"stop,omitempty"`
+}
+
+// Key 计算本次请求的缓存 key
+func (c *ChatModel) Key(input []*schema.Message, opts ...model.Option) (string, error) {
+	common := model.GetCommonOptions(&model.Options{Tools: c.tools}, opts...)
+	p := keyPayload{
+		Namespace:   c.namespace,
+		Messages:    input,
+		ToolChoice:  common.ToolChoice,
+		Model:       common.Model,
+		Temperature: common.Temperature,
+		TopP:        common.TopP,
+		MaxTokens:   common.MaxTokens,
+		Stop:        common.Stop,
+	}
+	for _, t := range common.Tools {
+		kt := keyTool{Name: t.Name, Desc: t.Desc}
+		if t.ParamsOneOf != nil {
+			params, err := canonicalSchema(t.ParamsOneOf)
+			if err != nil {
+				return "", err
+			}
+			kt.Params = params
+		}
+		p.Tools = append(p.Tools, kt)
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalSchema 把参数定义转成与顺序无关的形式
+// NewParamsOneOfByParams 由 map 生成 JSON Schema，properties / required 的顺序每次都可能不同
+func canonicalSchema(p *schema.ParamsOneOf) (any, error) {
+	js, err := p.ToJSONSchema()
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(js)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	sortRequired(v)
+	return v, nil
+}
+
+func sortRequired(v any) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if arr, ok := child.([]any); ok && k == "required" {
+				sort.Slice(arr, func(i, j int) bool { return fmt.Sprint(arr[i]) < fmt.Sprint(arr[j]) })
+				continue
+			}
+			sortRequired(child)
+		}
+	case []any:
+		for _, child := range t {
+			sortRequired(child)
+		}
+	}
+}
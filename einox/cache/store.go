@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Entry 一条缓存的模型响应
+// Generate 的结果存 Message；Stream 的结果按原分片存 Chunks，命中时逐片回放
+type Entry struct {
+	Key       string            `json:"key"`
+	CreatedAt time.Time         `json:"created_at"`
+	ExpiresAt time.Time         `json:"expires_at,omitempty"` // 零值表示永不过期
+	Message   *schema.Message   `json:"message,omitempty"`
+	Chunks    []*schema.Message `json:"chunks,omitempty"`
+}
+
+// Expired 判断缓存是否已过期
+func (e *Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && now.After(e.ExpiresAt)
+}
+
+// DiskStore 以 JSON 文件存储缓存，路径为 {Dir}/{key[:2]}/{key}.json
+type DiskStore struct {
+	dir string
+}
+
+// NewDiskStore 创建磁盘缓存目录
+func NewDiskStore(dir string) (*DiskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskStore{dir: dir}, nil
+}
+
+func (s *DiskStore) path(key string) string {
+	return filepath.Join(s.dir, key[:2], key+".json")
+}
+
+// Get 读取缓存，不存在或已过期时返回 nil
+func (s *DiskStore) Get(key string) (*Entry, error) {
+	b, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{}
+	if err := json.Unmarshal(b, e); err != nil {
+		return nil, err
+	}
+	if e.Expired(time.Now()) {
+		_ = os.Remove(s.path(key))
+		return nil, nil
+	}
+	return e, nil
+}
+
+// Put 写入缓存；先写临时文件再 rename，避免并发读到半个文件
+func (s *DiskStore) Put(e *Entry) error {
+	path := s.path(e.Key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), e.Key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Delete 删除一条缓存
+func (s *DiskStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Prune 清理所有已过期的缓存，返回删除的条数
+func (s *DiskStore) Prune() (int, error) {
+	now := time.Now()
+	removed := 0
+	err := filepath.WalkDir(s.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".json" {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		e := &Entry{}
+		if json.Unmarshal(b, e) != nil || e.Expired(now) {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
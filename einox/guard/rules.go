@@ -0,0 +1,83 @@
+package guard
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Rule 一条启发式规则：命中即计入 Weight（0~1）
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Weight  float64
+}
+
+// DefaultRules 常见的提示词注入与越狱写法（中英文）
+// 多条命中时按 1-∏(1-w) 合并，单条规则不会把分数推到 1
+var DefaultRules = []Rule{
+	{"ignore_instructions", regexp.MustCompile(`(?i)\b(ignore|forget|disregard)\s+(all\s+|any\s+)?(the\s+|your\s+)?(previous|prior|above|earlier|preceding)\s+(instructions?|prompts?|rules|directions)`), 0.7},
+	{"ignore_instructions", regexp.MustCompile(`(忽略|无视|忘记|忘掉)(掉)?(你)?(之前|以上|上面|前面|先前|所有|全部)(的)?(所有|全部)?(指令|指示|提示|提示词|规则|要求|设定)`), 0.7},
+	{"override_rules", regexp.MustCompile(`(?i)\b(override|bypass|disable|ignore)\s+(the\s+|your\s+|all\s+)?(safety|security|content)?\s*(rules|guidelines|restrictions|filters|policies)`), 0.5},
+	{"override_rules", regexp.MustCompile(`(绕过|跳过|解除|无视|关闭)(所有|全部)?(的)?(安全|内容)?(限制|规则|审查|过滤|策略)`), 0.5},
+	{"role_override", regexp.MustCompile(`(?i)\b(you\s+are\s+now|from\s+now\s+on,?\s+you|pretend\s+(to\s+be|you\s+are)|act\s+as\s+(an?\s+)?(unrestricted|unfiltered|jailbroken))`), 0.4},
+	{"role_override", regexp.MustCompile(`(从现在(开始|起)，?你(就)?是|你现在(的身份)?是一个(不受限制|没有限制)|扮演一个(不受限制|没有任何限制))`), 0.4},
+	{"prompt_leak", regexp.MustCompile(`(?i)\b(reveal|show|print|repeat|output|leak|tell\s+me)\b.{0,30}\b(system\s+prompt|initial\s+prompt|hidden\s+instructions|your\s+instructions)`), 0.5},
+	{"prompt_leak", regexp.MustCompile(`(输出|显示|打印|重复|告诉我|泄露).{0,12}(系统提示|system prompt|你的(指令|提示词|设定)|初始提示)`), 0.5},
+	{"jailbreak", regexp.MustCompile(`(?i)\b(DAN\s+mode|do\s+anything\s+now|developer\s+mode|jailbreak(ed)?)\b`), 0.6},
+	{"jailbreak", regexp.MustCompile(`(越狱模式|开发者模式|解除限制模式)`), 0.6},
+	{"fake_role", regexp.MustCompile(`(?im)^\s*(system|assistant|developer)\s*[:：]`), 0.4},
+	{"fake_role", regexp.MustCompile(`(?i)(<\|im_start\|>|<\|im_end\|>|<\|system\|>|\[/?INST\]|<<SYS>>|</?(system|instructions?)>)`), 0.6},
+	{"exfiltration", regexp.MustCompile(`(?i)\b(send|post|upload|forward)\b.{0,40}\b(password|api[\s_-]?key|token|secret|credentials)\b`), 0.4},
+	{"exfiltration", regexp.MustCompile(`(发送|上传|转发|泄露).{0,12}(密码|密钥|api ?key|令牌|凭证)`), 0.4},
+	{"invisible_chars", invisibleRe, 0.3},
+}
+
+// invisibleRe 零宽字符、双向控制符与 Unicode tag 字符，常用来隐藏注入内容
+var invisibleRe = regexp.MustCompile(`[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2060}-\x{2064}\x{FEFF}\x{E0000}-\x{E007F}]`)
+
+// Finding 一次规则命中
+type Finding struct {
+	Rule   string  `json:"rule"`
+	Match  string  `json:"match"`
+	Weight float64 `json:"weight"`
+}
+
+// score 用规则扫描文本，返回合并后的分数与命中明细；同名规则只计一次
+func score(rules []Rule, text string) (float64, []Finding) {
+	var findings []Finding
+	seen := make(map[string]bool)
+	remain := 1.0
+	for _, r := range rules {
+		m := r.Pattern.FindString(text)
+		if m == "" {
+			continue
+		}
+		findings = append(findings, Finding{Rule: r.Name, Match: truncate(m, 60), Weight: r.Weight})
+		if seen[r.Name] {
+			continue
+		}
+		seen[r.Name] = true
+		remain *= 1 - r.Weight
+	}
+	return 1 - remain, findings
+}
+
+// sanitize 去掉命中的片段与不可见字符
+func sanitize(rules []Rule, text string) string {
+	text = invisibleRe.ReplaceAllString(text, "")
+	for _, r := range rules {
+		if r.Pattern == invisibleRe {
+			continue
+		}
+		text = r.Pattern.ReplaceAllString(text, "[已移除]")
+	}
+	return text
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
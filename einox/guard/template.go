@@ -0,0 +1,82 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Template 包装 ChatTemplate：Format 前检查 keys 对应的字符串变量
+// 被拒绝时返回 *BlockedError；有内容被加上分隔符时，在 system 消息末尾追加 FenceInstruction（没有 system 消息则插入一条）
+func (g *Guard) Template(inner prompt.ChatTemplate, keys ...string) prompt.ChatTemplate {
+	return &guardedTemplate{guard: g, inner: inner, keys: keys}
+}
+
+type guardedTemplate struct {
+	guard *Guard
+	inner prompt.ChatTemplate
+	keys  []string
+}
+
+func (t *guardedTemplate) Format(ctx context.Context, vs map[string]any, opts ...prompt.Option) ([]*schema.Message, error) {
+	values := make(map[string]any, len(vs))
+	for k, v := range vs {
+		values[k] = v
+	}
+	fenced := false
+	for _, key := range t.keys {
+		text, ok := values[key].(string)
+		if !ok || text == "" {
+			continue
+		}
+		d, err := t.guard.Check(ctx, key, text)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = d.Content
+		fenced = fenced || d.Action == ActionFence || t.guard.alwaysFence && d.Action == ActionSanitize
+	}
+
+	messages, err := t.inner.Format(ctx, values, opts...)
+	if err != nil || !fenced {
+		return messages, err
+	}
+	for i, m := range messages {
+		if m.Role == schema.System {
+			c := *m
+			c.Content += "\n\n" + FenceInstruction
+			messages[i] = &c
+			return messages, nil
+		}
+	}
+	return append([]*schema.Message{schema.SystemMessage(FenceInstruction)}, messages...), nil
+}
+
+// Documents 检查检索回来的文档：被拒绝的丢弃，其余按结论替换为处理后的内容
+// 返回的是副本，不修改原文档；Decision 同时写入 MetaData["guard"]
+func (g *Guard) Documents(ctx context.Context, docs []*schema.Document) ([]*schema.Document, error) {
+	out := make([]*schema.Document, 0, len(docs))
+	for i, d := range docs {
+		source := d.ID
+		if source == "" {
+			source = fmt.Sprintf("doc%d", i+1)
+		}
+		decision, err := g.Check(ctx, source, d.Content)
+		if errors.Is(err, ErrBlocked) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		meta := make(map[string]any, len(d.MetaData)+1)
+		for k, v := range d.MetaData {
+			meta[k] = v
+		}
+		meta["guard"] = decision
+		out = append(out, &schema.Document{ID: d.ID, Content: decision.Content, MetaData: meta})
+	}
+	return out, nil
+}
@@ -0,0 +1,310 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Deleter 从目标索引中删除指定 ID 的切片；eino 的 Indexer 接口没有删除能力，需要按存储单独实现
+type Deleter interface {
+	Delete(ctx context.Context, ids []string) error
+}
+
+// DeleteFunc 把普通函数适配为 Deleter
+type DeleteFunc func(ctx context.Context, ids []string) error
+
+func (f DeleteFunc) Delete(ctx context.Context, ids []string) error {
+	return f(ctx, ids)
+}
+
+// Config 增量入库配置
+type Config struct {
+	// ManifestPath 清单文件路径
+	ManifestPath string
+	// Loader 按 URI 加载单个文件，如使用 ExtParser 的 file loader
+	Loader document.Loader
+	// Transformer 可选的切分器
+	Transformer document.Transformer
+	Indexer     indexer.Indexer
+	// Deleter 删除变更或移除文件的旧切片；为空或删除失败时，移除的文件会报错并保留在清单中，
+	// 更新文件未删掉的旧切片记入 FileEntry.PendingDelete，下次运行时重试
+	Deleter Deleter
+	// EmbeddingModel 当前使用的向量模型，清单中记录的模型不同的文件会重建
+	EmbeddingModel string
+	// Pipeline 解析 / 切分配置的版本标识（如 recursive-500-50），变化后同样重建
+	Pipeline string
+}
+
+// Action 对一个文件的处理
+type Action string
+
+const (
+	ActionSkip   Action = "skip"
+	ActionAdd    Action = "add"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Change 计划中的一项
+type Change struct {
+	URI    string
+	Action Action
+	// Reason 需要处理的原因，如 new file / content changed / embedding model changed
+	Reason string
+	// Err 读取文件失败时非空，此时 Action 为 skip，清单中的旧记录保留
+	Err  error
+	hash string
+	size int64
+}
+
+// FileError 单个文件处理失败；失败的文件保留旧记录，下次运行重试
+type FileError struct {
+	URI string
+	Err error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URI, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Result 一次增量入库的结果
+type Result struct {
+	Changes       []Change
+	Errors        []*FileError
+	ChunksStored  int
+	ChunksDeleted int
+}
+
+// Count 统计某种处理的文件数
+func (r *Result) Count(a Action) int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Action == a {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Result) String() string {
+	return fmt.Sprintf("新增 %d，更新 %d，跳过 %d，删除 %d，失败 %d；写入切片 %d，删除切片 %d",
+		r.Count(ActionAdd), r.Count(ActionUpdate), r.Count(ActionSkip), r.Count(ActionDelete),
+		len(r.Errors), r.ChunksStored, r.ChunksDeleted)
+}
+
+// Ingester 按清单做增量入库：未变化的文件跳过，变化的重建，已移除的删除切片
+type Ingester struct {
+	cfg *Config
+}
+
+// New 创建增量入库器
+func New(cfg *Config) (*Ingester, error) {
+	if cfg == nil || cfg.ManifestPath == "" || cfg.Loader == nil || cfg.Indexer == nil {
+		return nil, errors.New("manifest path, loader and indexer are required")
+	}
+	return &Ingester{cfg: cfg}, nil
+}
+
+// Plan 对比当前文件与清单，给出每个文件的处理方式，不做任何写入
+func (in *Ingester) Plan(ctx context.Context, uris []string) ([]Change, error) {
+	m, err := LoadManifest(in.cfg.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	return in.plan(ctx, m, uris)
+}
+
+func (in *Ingester) plan(ctx context.Context, m *Manifest, uris []string) ([]Change, error) {
+	var changes []Change
+	seen := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if seen[uri] {
+			continue
+		}
+		seen[uri] = true
+		hash, size, err := hashFile(uri)
+		c := Change{URI: uri, Err: err, hash: hash, size: size}
+		old, ok := m.Files[uri]
+		switch {
+		case err != nil:
+			c.Action = ActionSkip
+		case !ok:
+			c.Action, c.Reason = ActionAdd, "new file"
+		case old.EmbeddingModel != in.cfg.EmbeddingModel:
+			c.Action, c.Reason = ActionUpdate, fmt.Sprintf("embedding model changed (%s -> %s)", old.EmbeddingModel, in.cfg.EmbeddingModel)
+		case old.Pipeline != in.cfg.Pipeline:
+			c.Action, c.Reason = ActionUpdate, fmt.Sprintf("pipeline changed (%s -> %s)", old.Pipeline, in.cfg.Pipeline)
+		case old.Hash != hash:
+			c.Action, c.Reason = ActionUpdate, "content changed"
+		default:
+			c.Action = ActionSkip
+			if n := len(old.PendingDelete); n > 0 {
+				c.Reason = fmt.Sprintf("retry %d pending deletes", n)
+			}
+		}
+		changes = append(changes, c)
+	}
+	for _, uri := range m.URIs() {
+		if !seen[uri] {
+			changes = append(changes, Change{URI: uri, Action: ActionDelete, Reason: "file removed"})
+		}
+	}
+	return changes, nil
+}
+
+// Run 对 uris 做增量入库并更新清单；单个文件失败不影响其他文件，失败明细见 Result.Errors
+// 清单在结束时保存（包括部分失败的情况），只有清单读写失败或 ctx 取消时返回错误
+func (in *Ingester) Run(ctx context.Context, uris []string) (*Result, error) {
+	m, err := LoadManifest(in.cfg.ManifestPath)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := in.plan(ctx, m, uris)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Changes: changes}
+	for _, c := range changes {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		if c.Err != nil {
+			res.Errors = append(res.Errors, &FileError{URI: c.URI, Err: c.Err})
+			continue
+		}
+		switch c.Action {
+		case ActionSkip:
+			entry := m.Files[c.URI]
+			if entry == nil || len(entry.PendingDelete) == 0 {
+				continue
+			}
+			if err := in.delete(ctx, entry.PendingDelete); err != nil {
+				res.Errors = append(res.Errors, &FileError{URI: c.URI, Err: fmt.Errorf("delete %d pending chunks: %w", len(entry.PendingDelete), err)})
+				continue
+			}
+			res.ChunksDeleted += len(entry.PendingDelete)
+			entry.PendingDelete = nil
+		case ActionAdd, ActionUpdate:
+			stored, deleted, err := in.index(ctx, m, c)
+			res.ChunksStored += stored
+			res.ChunksDeleted += deleted
+			if err != nil {
+				res.Errors = append(res.Errors, &FileError{URI: c.URI, Err: err})
+			}
+		case ActionDelete:
+			ids := staleIDs(m.Files[c.URI], nil)
+			if err := in.delete(ctx, ids); err != nil {
+				res.Errors = append(res.Errors, &FileError{URI: c.URI, Err: err})
+				continue
+			}
+			res.ChunksDeleted += len(ids)
+			delete(m.Files, c.URI)
+		}
+	}
+
+	if err := m.Save(in.cfg.ManifestPath); err != nil {
+		return res, err
+	}
+	return res, ctx.Err()
+}
+
+// index 加载、切分、写入一个文件，再删除上一版本多出来的切片
+// 先写后删：更新期间检索仍能命中旧内容
+func (in *Ingester) index(ctx context.Context, m *Manifest, c Change) (stored, deleted int, err error) {
+	docs, err := in.cfg.Loader.Load(ctx, document.Source{URI: c.URI})
+	if err != nil {
+		return 0, 0, fmt.Errorf("load: %w", err)
+	}
+	if in.cfg.Transformer != nil {
+		if docs, err = in.cfg.Transformer.Transform(ctx, docs); err != nil {
+			return 0, 0, fmt.Errorf("transform: %w", err)
+		}
+	}
+	docs = slices.DeleteFunc(docs, func(d *schema.Document) bool {
+		return d == nil || strings.TrimSpace(d.Content) == ""
+	})
+
+	// 切片 ID 由 URI 和序号决定：文件更新后同位置的切片直接覆盖
+	prefix := chunkPrefix(c.URI)
+	for i, d := range docs {
+		d.ID = fmt.Sprintf("%s-%d", prefix, i)
+		if d.MetaData == nil {
+			d.MetaData = map[string]any{}
+		}
+		d.MetaData["_source"] = c.URI
+		d.MetaData["_content_hash"] = c.hash
+	}
+	var ids []string
+	if len(docs) > 0 {
+		if ids, err = in.cfg.Indexer.Store(ctx, docs); err != nil {
+			return 0, 0, fmt.Errorf("store: %w", err)
+		}
+	}
+
+	stale := staleIDs(m.Files[c.URI], ids)
+	entry := &FileEntry{
+		URI:            c.URI,
+		Hash:           c.hash,
+		Size:           c.size,
+		ChunkIDs:       ids,
+		EmbeddingModel: in.cfg.EmbeddingModel,
+		Pipeline:       in.cfg.Pipeline,
+		IndexedAt:      time.Now().UTC(),
+	}
+	m.Files[c.URI] = entry
+	if err := in.delete(ctx, stale); err != nil {
+		// 新内容已写入，清单照常更新；未删掉的旧切片留在清单中，下次运行时重试
+		entry.PendingDelete = stale
+		return len(ids), 0, fmt.Errorf("delete %d stale chunks: %w", len(stale), err)
+	}
+	return len(ids), len(stale), nil
+}
+
+// staleIDs 旧记录中的切片（含上次未删掉的）里不在 keep 中的部分
+func staleIDs(old *FileEntry, keep []string) []string {
+	if old == nil {
+		return nil
+	}
+	var stale []string
+	for _, id := range slices.Concat(old.ChunkIDs, old.PendingDelete) {
+		if !slices.Contains(keep, id) && !slices.Contains(stale, id) {
+			stale = append(stale, id)
+		}
+	}
+	return stale
+}
+
+func (in *Ingester) delete(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if in.cfg.Deleter == nil {
+		return errors.New("no deleter configured")
+	}
+	return in.cfg.Deleter.Delete(ctx, ids)
+}
+
+func chunkPrefix(uri string) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, uri)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
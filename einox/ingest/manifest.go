@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Manifest 记录每个源文件上次入库时的状态，JSON 存储
+type Manifest struct {
+	Files map[string]*FileEntry `json:"files"`
+}
+
+// FileEntry 一个源文件的入库记录
+type FileEntry struct {
+	URI      string   `json:"uri"`
+	Hash     string   `json:"hash"`
+	Size     int64    `json:"size"`
+	ChunkIDs []string `json:"chunk_ids"`
+	// PendingDelete 上一版本中未能删除的旧切片（Deleter 失败或未配置），下次运行时重试
+	PendingDelete []string `json:"pending_delete,omitempty"`
+	// EmbeddingModel 入库时使用的向量模型，变化后需要重新向量化
+	EmbeddingModel string `json:"embedding_model"`
+	// Pipeline 入库时解析 / 切分配置的版本，变化后同样需要重建
+	Pipeline  string    `json:"pipeline,omitempty"`
+	IndexedAt time.Time `json:"indexed_at"`
+}
+
+// LoadManifest 读取清单，文件不存在时返回空清单
+func LoadManifest(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{Files: map[string]*FileEntry{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	if m.Files == nil {
+		m.Files = map[string]*FileEntry{}
+	}
+	return &m, nil
+}
+
+// Save 先写临时文件再重命名，中途退出不会留下半个清单
+func (m *Manifest) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// URIs 按字典序返回清单中的全部源文件
+func (m *Manifest) URIs() []string {
+	uris := make([]string, 0, len(m.Files))
+	for uri := range m.Files {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	return uris
+}
+
+// hashFile 文件内容的 sha256
+func hashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
@@ -0,0 +1,320 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// 与 eino-ext 文件加载器一致的元数据 key，下游切分 / 索引代码不用区分来源
+const (
+	MetaKeyFileName  = "_file_name"
+	MetaKeyExtension = "_extension"
+	MetaKeySource    = "_source"
+	// MetaKeyRelPath 相对根目录的路径
+	MetaKeyRelPath = "_rel_path"
+	// MetaKeySize 文件大小（字节）
+	MetaKeySize = "_size"
+	// MetaKeyModTime 修改时间（RFC3339）
+	MetaKeyModTime = "_mod_time"
+)
+
+// DirConfig 目录加载配置
+type DirConfig struct {
+	// Parser 每个文件的解析器，通常是按扩展名分发的 parser.ExtParser；只用 List 时可以为空
+	Parser parser.Parser
+	// Include 只加载匹配的文件，为空表示全部；Exclude 优先于 Include，匹配到的目录整个跳过
+	Include []string
+	Exclude []string
+	// MaxFileSize 单个文件大小上限，默认 20MB，负数表示不限制
+	MaxFileSize int64
+	// IncludeHidden 加载以 . 开头的文件和目录，默认跳过
+	IncludeHidden bool
+	// FollowSymlinks 跟随符号链接（会检测循环），默认跳过符号链接
+	FollowSymlinks bool
+	// Concurrency 同时解析的文件数，默认 4
+	Concurrency int
+}
+
+// FileError 单个文件加载失败
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Skipped 被策略跳过的文件
+type Skipped struct {
+	Path   string
+	Reason string
+}
+
+// Result 一次目录加载的结果，按文件路径排序
+type Result struct {
+	Docs    []*schema.Document
+	Files   int
+	Errors  []*FileError
+	Skipped []Skipped
+}
+
+// DirLoader 递归加载目录下的文件
+type DirLoader struct {
+	parser         parser.Parser
+	include        []*glob
+	exclude        []*glob
+	maxSize        int64
+	includeHidden  bool
+	followSymlinks bool
+	concurrency    int
+}
+
+var _ document.Loader = (*DirLoader)(nil)
+
+// NewDirLoader 创建目录加载器
+func NewDirLoader(cfg *DirConfig) (*DirLoader, error) {
+	if cfg == nil {
+		cfg = &DirConfig{}
+	}
+	include, err := compileGlobs(cfg.Include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compileGlobs(cfg.Exclude)
+	if err != nil {
+		return nil, err
+	}
+	l := &DirLoader{
+		parser:         cfg.Parser,
+		include:        include,
+		exclude:        exclude,
+		maxSize:        cfg.MaxFileSize,
+		includeHidden:  cfg.IncludeHidden,
+		followSymlinks: cfg.FollowSymlinks,
+		concurrency:    cfg.Concurrency,
+	}
+	if l.maxSize == 0 {
+		l.maxSize = 20 << 20
+	}
+	if l.concurrency <= 0 {
+		l.concurrency = 4
+	}
+	return l, nil
+}
+
+// Load 实现 document.Loader，Source.URI 为目录路径
+// 单个文件失败只记录日志，不中断整批；需要失败明细时使用 LoadDir
+func (l *DirLoader) Load(ctx context.Context, src document.Source, opts ...document.LoaderOption) ([]*schema.Document, error) {
+	res, err := l.LoadDir(ctx, src.URI, document.GetLoaderCommonOptions(&document.LoaderOptions{}, opts...).ParserOptions...)
+	if err != nil {
+		return nil, err
+	}
+	for _, fe := range res.Errors {
+		log.Printf("[DirLoader] %v", fe)
+	}
+	return res.Docs, nil
+}
+
+// LoadDir 遍历 root 并解析每个文件；只有根目录不可读或 ctx 取消时返回错误
+func (l *DirLoader) LoadDir(ctx context.Context, root string, opts ...parser.Option) (*Result, error) {
+	if l.parser == nil {
+		return nil, errors.New("parser is required")
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	res := &Result{}
+	var files []walkedFile
+	if err := l.walk(ctx, root, "", map[string]bool{}, &files, res); err != nil {
+		return nil, err
+	}
+	res.Files = len(files)
+
+	docs := make([][]*schema.Document, len(files))
+	errs := make([]error, len(files))
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, l.concurrency)
+	)
+	for i, f := range files {
+		wg.Add(1)
+		go func(i int, f walkedFile) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			docs[i], errs[i] = l.parseFile(ctx, f, opts)
+		}(i, f)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, f := range files {
+		if errs[i] != nil {
+			res.Errors = append(res.Errors, &FileError{Path: f.path, Err: errs[i]})
+			continue
+		}
+		res.Docs = append(res.Docs, docs[i]...)
+	}
+	sort.Slice(res.Errors, func(i, j int) bool { return res.Errors[i].Path < res.Errors[j].Path })
+	sort.Slice(res.Skipped, func(i, j int) bool { return res.Skipped[i].Path < res.Skipped[j].Path })
+	return res, nil
+}
+
+// List 按同样的过滤与跳过策略列出 root 下要加载的文件路径，不解析内容
+// 增量入库等需要自行处理文件的场景使用
+func (l *DirLoader) List(ctx context.Context, root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+	var files []walkedFile
+	if err := l.walk(ctx, root, "", map[string]bool{}, &files, &Result{}); err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+type walkedFile struct {
+	path string // 用于打开文件的路径
+	rel  string // 相对根目录，以 / 分隔
+	info os.FileInfo
+}
+
+// walk 按名称顺序遍历目录；visited 记录已进入目录的真实路径，防止符号链接成环
+func (l *DirLoader) walk(ctx context.Context, dir, rel string, visited map[string]bool, files *[]walkedFile, res *Result) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		if visited[real] {
+			res.Skipped = append(res.Skipped, Skipped{Path: dir, Reason: "symlink loop"})
+			return nil
+		}
+		visited[real] = true
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if rel == "" {
+			return err
+		}
+		res.Errors = append(res.Errors, &FileError{Path: dir, Err: err})
+		return nil
+	}
+	for _, e := range entries {
+		p, r := filepath.Join(dir, e.Name()), path.Join(rel, e.Name())
+		if !l.includeHidden && strings.HasPrefix(e.Name(), ".") {
+			res.Skipped = append(res.Skipped, Skipped{Path: p, Reason: "hidden"})
+			continue
+		}
+		if matchAny(l.exclude, r) {
+			res.Skipped = append(res.Skipped, Skipped{Path: p, Reason: "excluded"})
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			res.Errors = append(res.Errors, &FileError{Path: p, Err: err})
+			continue
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !l.followSymlinks {
+				res.Skipped = append(res.Skipped, Skipped{Path: p, Reason: "symlink"})
+				continue
+			}
+			if info, err = os.Stat(p); err != nil {
+				res.Errors = append(res.Errors, &FileError{Path: p, Err: err})
+				continue
+			}
+		}
+
+		switch {
+		case info.IsDir():
+			if err := l.walk(ctx, p, r, visited, files, res); err != nil {
+				return err
+			}
+		case !info.Mode().IsRegular():
+			res.Skipped = append(res.Skipped, Skipped{Path: p, Reason: "not a regular file"})
+		case len(l.include) > 0 && !matchAny(l.include, r):
+			// 未被 include 选中的文件不算跳过，避免结果里堆满无关文件
+		case l.maxSize > 0 && info.Size() > l.maxSize:
+			res.Skipped = append(res.Skipped, Skipped{Path: p, Reason: fmt.Sprintf("size %d exceeds %d", info.Size(), l.maxSize)})
+		default:
+			*files = append(*files, walkedFile{path: p, rel: r, info: info})
+		}
+	}
+	return nil
+}
+
+// parseFile 交给解析器处理，补充文件元数据；解析器没有给出 ID 时用相对路径作为 ID
+func (l *DirLoader) parseFile(ctx context.Context, f walkedFile, opts []parser.Option) (docs []*schema.Document, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parser panic: %v", r)
+		}
+	}()
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	meta := map[string]any{
+		MetaKeyFileName:  filepath.Base(f.path),
+		MetaKeyExtension: filepath.Ext(f.path),
+		MetaKeySource:    f.path,
+		MetaKeyRelPath:   f.rel,
+		MetaKeySize:      f.info.Size(),
+		MetaKeyModTime:   f.info.ModTime().UTC().Format(time.RFC3339),
+	}
+	docs, err = l.parser.Parse(ctx, file, append([]parser.Option{parser.WithURI(f.path), parser.WithExtraMeta(meta)}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	for i, d := range docs {
+		if d == nil || d.ID != "" {
+			continue
+		}
+		d.ID = f.rel
+		if len(docs) > 1 {
+			d.ID = fmt.Sprintf("%s#%d", f.rel, i)
+		}
+	}
+	return docs, nil
+}
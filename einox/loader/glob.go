@@ -0,0 +1,118 @@
+package loader
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// glob 编译后的路径模式
+// 语法：* 匹配除 / 外的任意字符，** 匹配任意层目录，? 匹配单个字符，[abc] 字符集，{a,b} 任选其一
+// 不含 / 的模式只匹配文件名（类似 .gitignore），如 *.md 匹配任意目录下的 md 文件
+type glob struct {
+	pattern  string
+	re       *regexp.Regexp
+	baseOnly bool
+}
+
+func compileGlobs(patterns []string) ([]*glob, error) {
+	out := make([]*glob, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := compileGlob(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+func compileGlob(pattern string) (*glob, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("empty glob pattern")
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	depth := 0 // {} 嵌套层数
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(p[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("glob %q: unclosed [", pattern)
+			}
+			class := p[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end
+		case '{':
+			depth++
+			b.WriteString("(?:")
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("glob %q: unbalanced }", pattern)
+			}
+			depth--
+			b.WriteString(")")
+		case ',':
+			if depth > 0 {
+				b.WriteString("|")
+			} else {
+				b.WriteString(",")
+			}
+		default:
+			// 按完整的 UTF-8 字符转义，非 ASCII 路径（如 文档/*.md）才能正确匹配
+			_, size := utf8.DecodeRuneInString(p[i:])
+			b.WriteString(regexp.QuoteMeta(p[i : i+size]))
+			i += size - 1
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("glob %q: unbalanced {", pattern)
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("glob %q: %w", pattern, err)
+	}
+	return &glob{pattern: pattern, re: re, baseOnly: !strings.Contains(p, "/")}, nil
+}
+
+// match rel 为相对根目录、以 / 分隔的路径
+func (g *glob) match(rel string) bool {
+	if g.baseOnly {
+		if i := strings.LastIndexByte(rel, '/'); i >= 0 {
+			rel = rel[i+1:]
+		}
+	}
+	return g.re.MatchString(rel)
+}
+
+func matchAny(globs []*glob, rel string) bool {
+	for _, g := range globs {
+		if g.match(rel) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,54 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// command einox 子命令
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "serve", usage: "启动 einox 服务（/metrics、/healthz）", run: runServe},
+	{name: "trace", usage: "查看执行追踪：trace show <run-id|latest>", run: runTrace},
+	{name: "prompt-eval", usage: "在 JSONL 数据集上对比提示词版本 / 模型的胜率、成本与延迟", run: runPromptEval},
+}
 
 func main() {
-	fmt.Println("Hello, Einox!")
-	fmt.Println("模块二：Eino 实战项目篇（to be implemented）")
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		usage()
+		return
+	}
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				log.Fatalf("einox %s: %v", name, err)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Einox - 模块二：Eino 实战项目篇")
+	fmt.Fprintln(os.Stderr, "\n用法: einox <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\n命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.name, cmd.usage)
+	}
 }
@@ -0,0 +1,141 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Capabilities 模型支持的输入模态，文本总是支持
+type Capabilities struct {
+	Image bool
+	Audio bool
+	Video bool
+	File  bool
+}
+
+// Known 常用模型的输入能力；不在表中的模型需要显式声明
+var Known = map[string]Capabilities{
+	"deepseek-chat":     {},
+	"deepseek-reasoner": {},
+}
+
+// ErrUnsupportedModality 模型不支持消息中的某种输入
+var ErrUnsupportedModality = errors.New("media: unsupported modality")
+
+// CapabilityError 指出哪条消息的哪种片段不被支持
+type CapabilityError struct {
+	Model    string
+	Modality schema.ChatMessagePartType
+	// Index 消息在输入中的下标
+	Index int
+}
+
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf("media: model %s does not accept %s input (message %d)", e.Model, e.Modality, e.Index)
+}
+
+func (e *CapabilityError) Is(target error) bool {
+	return target == ErrUnsupportedModality
+}
+
+// Supports 是否支持该类型的片段
+func (c Capabilities) Supports(t schema.ChatMessagePartType) bool {
+	switch t {
+	case schema.ChatMessagePartTypeText:
+		return true
+	case schema.ChatMessagePartTypeImageURL:
+		return c.Image
+	case schema.ChatMessagePartTypeAudioURL:
+		return c.Audio
+	case schema.ChatMessagePartTypeVideoURL:
+		return c.Video
+	case schema.ChatMessagePartTypeFileURL:
+		return c.File
+	}
+	return false
+}
+
+// Require 检查消息中的多模态片段是否都被支持，返回第一个不支持的 *CapabilityError
+func Require(modelName string, caps Capabilities, input []*schema.Message) error {
+	for i, m := range input {
+		if m == nil {
+			continue
+		}
+		for _, p := range m.UserInputMultiContent {
+			if !caps.Supports(p.Type) {
+				return &CapabilityError{Model: modelName, Modality: p.Type, Index: i}
+			}
+		}
+		for _, p := range m.MultiContent {
+			if !caps.Supports(p.Type) {
+				return &CapabilityError{Model: modelName, Modality: p.Type, Index: i}
+			}
+		}
+	}
+	return nil
+}
+
+// ChatModel 在请求前检查输入模态的 ChatModel 装饰器
+// 不支持视觉的模型收到图片时直接返回 *CapabilityError，而不是让服务商报一个含糊的错误或静默丢弃
+type ChatModel struct {
+	inner model.BaseChatModel
+	name  string
+	caps  Capabilities
+}
+
+var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+
+// NewChatModel 包装 ChatModel；caps 为 nil 时按 Known 查找 modelName，查不到视为只支持文本
+func NewChatModel(inner model.BaseChatModel, modelName string, caps *Capabilities) (*ChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("inner chat model is required")
+	}
+	c := Known[modelName]
+	if caps != nil {
+		c = *caps
+	}
+	return &ChatModel{inner: inner, name: modelName, caps: c}, nil
+}
+
+func (c *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	if err := Require(c.name, c.caps, input); err != nil {
+		return nil, err
+	}
+	return c.inner.Generate(ctx, input, opts...)
+}
+
+func (c *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := Require(c.name, c.caps, input); err != nil {
+		return nil, err
+	}
+	return c.inner.Stream(ctx, input, opts...)
+}
+
+// WithTools 返回绑定了工具的新实例，能力声明不变
+func (c *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tc, ok := c.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("inner chat model does not implement ToolCallingChatModel")
+	}
+	inner, err := tc.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{inner: inner, name: c.name, caps: c.caps}, nil
+}
+
+// GetType 沿用内部模型的类型，trace / metrics 中仍显示真实模型
+func (c *ChatModel) GetType() string {
+	typ, _ := components.GetType(c.inner)
+	return typ
+}
+
+// IsCallbacksEnabled 与内部模型保持一致，避免回调重复触发
+func (c *ChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.inner)
+}
@@ -0,0 +1,257 @@
+package media
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrTooLarge 文件超过大小限制
+var ErrTooLarge = errors.New("media: file too large")
+
+// ErrUnsupportedType 不支持的 MIME 类型
+var ErrUnsupportedType = errors.New("media: unsupported mime type")
+
+// ErrOutsideBaseDir 路径解析后不在 Loader.BaseDir 之下
+var ErrOutsideBaseDir = errors.New("media: path outside base dir")
+
+// ImageTypes 允许作为图片发送的 MIME 类型，主流视觉模型都支持
+var ImageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Loader 从本地文件或字节构造消息片段
+type Loader struct {
+	// MaxImageSize 图片大小上限（编码前），默认 10MB
+	MaxImageSize int64
+	// MaxFileSize 文件大小上限（编码前），默认 20MB
+	MaxFileSize int64
+	// Detail 图片清晰度，默认 auto
+	Detail schema.ImageURLDetail
+	// BaseDir 非空时只允许读取该目录下的文件：相对路径按 BaseDir 解析，
+	// 清理路径并解析符号链接后仍在目录之外的返回 ErrOutsideBaseDir
+	BaseDir string
+}
+
+// Default 使用默认限制的 Loader
+var Default = &Loader{}
+
+func (l *Loader) maxImage() int64 {
+	if l.MaxImageSize > 0 {
+		return l.MaxImageSize
+	}
+	return 10 << 20
+}
+
+func (l *Loader) maxFile() int64 {
+	if l.MaxFileSize > 0 {
+		return l.MaxFileSize
+	}
+	return 20 << 20
+}
+
+// Image 读取本地图片，按内容识别 MIME 类型并 base64 编码
+func (l *Loader) Image(path string) (schema.MessageInputPart, error) {
+	data, err := l.read(path, l.maxImage())
+	if err != nil {
+		return schema.MessageInputPart{}, err
+	}
+	return l.ImageBytes(data, filepath.Base(path))
+}
+
+// ImageBytes 用内存中的图片构造片段；name 仅在内容无法识别时用于按扩展名推断类型，可为空
+func (l *Loader) ImageBytes(data []byte, name string) (schema.MessageInputPart, error) {
+	if int64(len(data)) > l.maxImage() {
+		return schema.MessageInputPart{}, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrTooLarge, displayName(name), len(data), l.maxImage())
+	}
+	mimeType := DetectMIME(data, name)
+	if !ImageTypes[mimeType] {
+		return schema.MessageInputPart{}, fmt.Errorf("%w: %s is %s, not an image", ErrUnsupportedType, displayName(name), mimeType)
+	}
+	detail := l.Detail
+	if detail == "" {
+		detail = schema.ImageURLDetailAuto
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return schema.MessageInputPart{
+		Type: schema.ChatMessagePartTypeImageURL,
+		Image: &schema.MessageInputImage{
+			MessagePartCommon: schema.MessagePartCommon{Base64Data: &encoded, MIMEType: mimeType},
+			Detail:            detail,
+		},
+	}, nil
+}
+
+// File 读取本地文件（如 PDF），按内容识别 MIME 类型并 base64 编码
+func (l *Loader) File(path string) (schema.MessageInputPart, error) {
+	data, err := l.read(path, l.maxFile())
+	if err != nil {
+		return schema.MessageInputPart{}, err
+	}
+	return l.FileBytes(data, filepath.Base(path))
+}
+
+// FileBytes 用内存中的文件内容构造片段，name 作为文件名传给模型
+func (l *Loader) FileBytes(data []byte, name string) (schema.MessageInputPart, error) {
+	if int64(len(data)) > l.maxFile() {
+		return schema.MessageInputPart{}, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrTooLarge, displayName(name), len(data), l.maxFile())
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	return schema.MessageInputPart{
+		Type: schema.ChatMessagePartTypeFileURL,
+		File: &schema.MessageInputFile{
+			MessagePartCommon: schema.MessagePartCommon{Base64Data: &encoded, MIMEType: DetectMIME(data, name)},
+			Name:              name,
+		},
+	}, nil
+}
+
+// Path 按 MIME 类型自动选择：图片构造为图片片段，其余构造为文件片段
+func (l *Loader) Path(path string) (schema.MessageInputPart, error) {
+	data, err := l.read(path, max(l.maxImage(), l.maxFile()))
+	if err != nil {
+		return schema.MessageInputPart{}, err
+	}
+	name := filepath.Base(path)
+	if ImageTypes[DetectMIME(data, name)] {
+		return l.ImageBytes(data, name)
+	}
+	return l.FileBytes(data, name)
+}
+
+// Image 使用默认限制读取本地图片
+func Image(path string) (schema.MessageInputPart, error) { return Default.Image(path) }
+
+// ImageBytes 使用默认限制构造图片片段
+func ImageBytes(data []byte, name string) (schema.MessageInputPart, error) {
+	return Default.ImageBytes(data, name)
+}
+
+// File 使用默认限制读取本地文件
+func File(path string) (schema.MessageInputPart, error) { return Default.File(path) }
+
+// FileBytes 使用默认限制构造文件片段
+func FileBytes(data []byte, name string) (schema.MessageInputPart, error) {
+	return Default.FileBytes(data, name)
+}
+
+// ImageURL 引用远程图片，不下载也不校验
+func ImageURL(url string) schema.MessageInputPart {
+	return schema.MessageInputPart{
+		Type: schema.ChatMessagePartTypeImageURL,
+		Image: &schema.MessageInputImage{
+			MessagePartCommon: schema.MessagePartCommon{URL: &url},
+			Detail:            schema.ImageURLDetailAuto,
+		},
+	}
+}
+
+// Text 文本片段
+func Text(text string) schema.MessageInputPart {
+	return schema.MessageInputPart{Type: schema.ChatMessagePartTypeText, Text: text}
+}
+
+// UserMessage 构造多模态用户消息：text 非空时作为第一个片段
+func UserMessage(text string, parts ...schema.MessageInputPart) *schema.Message {
+	content := make([]schema.MessageInputPart, 0, len(parts)+1)
+	if text != "" {
+		content = append(content, Text(text))
+	}
+	content = append(content, parts...)
+	return &schema.Message{Role: schema.User, UserInputMultiContent: content}
+}
+
+// DetectMIME 先按内容识别，识别不出时按扩展名推断
+func DetectMIME(data []byte, name string) string {
+	detected := http.DetectContentType(data)
+	if base, _, _ := strings.Cut(detected, ";"); base != "application/octet-stream" && base != "text/plain" {
+		return base
+	}
+	if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(name))); byExt != "" {
+		base, _, _ := strings.Cut(byExt, ";")
+		return base
+	}
+	base, _, _ := strings.Cut(detected, ";")
+	return base
+}
+
+// read 把 path 限制在 BaseDir 内后读取
+func (l *Loader) read(path string, limit int64) ([]byte, error) {
+	resolved, err := l.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	return readLimited(resolved, limit)
+}
+
+// resolve 未设置 BaseDir 时原样返回；否则返回解析符号链接后的绝对路径，
+// 清理后或解析符号链接后不在 BaseDir 之下时报错
+func (l *Loader) resolve(path string) (string, error) {
+	if l.BaseDir == "" {
+		return path, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(l.BaseDir, path)
+	}
+	base, err := filepath.Abs(l.BaseDir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	// 先按字面路径检查，避免对目录之外的路径做任何文件系统访问
+	if !within(base, abs) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideBaseDir, path)
+	}
+	if base, err = filepath.EvalSymlinks(base); err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", err
+	}
+	if !within(base, resolved) {
+		return "", fmt.Errorf("%w: %s", ErrOutsideBaseDir, path)
+	}
+	return resolved, nil
+}
+
+// within path 是否是 dir 本身或其下的路径，两者都应是清理过的绝对路径
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// readLimited 读取文件，先按文件大小检查限制，避免把大文件整个读进内存
+func readLimited(path string, limit int64) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("media: %s is a directory", path)
+	}
+	if info.Size() > limit {
+		return nil, fmt.Errorf("%w: %s is %d bytes, limit %d", ErrTooLarge, path, info.Size(), limit)
+	}
+	return os.ReadFile(path)
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "data"
+	}
+	return name
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"github.com/NuyoahCh/einotelos/einox/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	limiterUtilizationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ratelimit", "utilization"),
+		"限流器令牌桶占用比例，resource 为 requests / tokens，超过 1 表示有调用在排队",
+		[]string{"limiter", "resource"}, nil,
+	)
+	limiterWaitingDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ratelimit", "waiting"),
+		"正在等待配额的调用数",
+		[]string{"limiter"}, nil,
+	)
+	limiterWaitDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "ratelimit", "wait_seconds_total"),
+		"等待配额的累计时长",
+		[]string{"limiter"}, nil,
+	)
+)
+
+// limiterCollector 在每次抓取时读取限流器的实时状态
+type limiterCollector struct {
+	limiters []*ratelimit.Limiter
+}
+
+// RegisterLimiters 把限流器的利用率注册到 reg
+func RegisterLimiters(reg prometheus.Registerer, limiters ...*ratelimit.Limiter) {
+	reg.MustRegister(&limiterCollector{limiters: limiters})
+}
+
+func (c *limiterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- limiterUtilizationDesc
+	ch <- limiterWaitingDesc
+	ch <- limiterWaitDesc
+}
+
+func (c *limiterCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, l := range c.limiters {
+		s := l.Stats()
+		ch <- prometheus.MustNewConstMetric(limiterUtilizationDesc, prometheus.GaugeValue, s.RequestUtilization, l.Name(), "requests")
+		ch <- prometheus.MustNewConstMetric(limiterUtilizationDesc, prometheus.GaugeValue, s.TokenUtilization, l.Name(), "tokens")
+		ch <- prometheus.MustNewConstMetric(limiterWaitingDesc, prometheus.GaugeValue, float64(s.Waiting), l.Name())
+		ch <- prometheus.MustNewConstMetric(limiterWaitDesc, prometheus.CounterValue, s.WaitTime.Seconds(), l.Name())
+	}
+}
@@ -0,0 +1,346 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/NuyoahCh/einotelos/einox/cache"
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+	callbacksHelper "github.com/cloudwego/eino/utils/callbacks"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "einox"
+
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// Metrics 模型、工具、向量化、检索调用的 Prometheus 指标
+// 通过 Handler() 挂到 Eino 的 callbacks 上自动采集
+type Metrics struct {
+	chatRequests  *prometheus.CounterVec
+	chatLatency   *prometheus.HistogramVec
+	chatTokens    *prometheus.CounterVec
+	toolCalls     *prometheus.CounterVec
+	toolLatency   *prometheus.HistogramVec
+	embedRequests *prometheus.CounterVec
+	embedBatch    *prometheus.HistogramVec
+	embedTokens   *prometheus.CounterVec
+	retrieveCalls *prometheus.CounterVec
+	retrieveTime  *prometheus.HistogramVec
+	retrieveHits  *prometheus.HistogramVec
+}
+
+// New 创建指标并注册到 reg
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		chatRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "chat_model_requests_total",
+			Help:      "ChatModel 请求次数",
+		}, []string{"model", "status"}),
+		chatLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "chat_model_latency_seconds",
+			Help:      "ChatModel 请求耗时（流式请求统计到最后一个分片）",
+			Buckets:   []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+		}, []string{"model"}),
+		chatTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "chat_model_tokens_total",
+			Help:      "ChatModel token 用量，type 为 prompt / completion；命中响应缓存的调用不计",
+		}, []string{"model", "type"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_invocations_total",
+			Help:      "工具调用次数",
+		}, []string{"tool", "status"}),
+		toolLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_latency_seconds",
+			Help:      "工具调用耗时",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool"}),
+		embedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "embedding_requests_total",
+			Help:      "Embedding 请求次数",
+		}, []string{"model", "status"}),
+		embedBatch: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "embedding_batch_size",
+			Help:      "每次 Embedding 请求的文本条数",
+			Buckets:   []float64{1, 2, 4, 8, 16, 32, 64, 128, 256},
+		}, []string{"model"}),
+		embedTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "embedding_tokens_total",
+			Help:      "Embedding token 用量",
+		}, []string{"model"}),
+		retrieveCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retriever_requests_total",
+			Help:      "检索请求次数",
+		}, []string{"retriever", "status"}),
+		retrieveTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "retriever_latency_seconds",
+			Help:      "检索耗时",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"retriever"}),
+		retrieveHits: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "retriever_hits",
+			Help:      "每次检索命中的文档数",
+			Buckets:   []float64{0, 1, 2, 3, 5, 10, 20, 50},
+		}, []string{"retriever"}),
+	}
+	reg.MustRegister(
+		m.chatRequests, m.chatLatency, m.chatTokens,
+		m.toolCalls, m.toolLatency,
+		m.embedRequests, m.embedBatch, m.embedTokens,
+		m.retrieveCalls, m.retrieveTime, m.retrieveHits,
+	)
+	return m
+}
+
+// NewRegistry 创建带 Go 运行时与进程指标的 Registry
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// HTTPHandler 返回 /metrics 的 HTTP Handler
+func HTTPHandler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// callState 组件开始时记录在 ctx 中的状态
+type callState struct {
+	start time.Time
+	label string
+}
+
+type callStateKey struct{}
+
+func withState(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, callStateKey{}, &callState{start: time.Now(), label: label})
+}
+
+func stateFrom(ctx context.Context, info *callbacks.RunInfo) *callState {
+	if s, ok := ctx.Value(callStateKey{}).(*callState); ok {
+		return s
+	}
+	return &callState{start: time.Now(), label: runInfoLabel(info)}
+}
+
+// runInfoLabel 没有更具体的名字时，用节点名或实现类型作为标签
+func runInfoLabel(info *callbacks.RunInfo) string {
+	if info == nil {
+		return "unknown"
+	}
+	if info.Name != "" {
+		return info.Name
+	}
+	if info.Type != "" {
+		return info.Type
+	}
+	return string(info.Component)
+}
+
+// Handler 返回采集指标的 callbacks.Handler
+// 可通过 compose.WithCallbacks 挂到单次执行上，或 callbacks.AppendGlobalHandlers 全局生效
+func (m *Metrics) Handler() callbacks.Handler {
+	return callbacksHelper.NewHandlerHelper().
+		ChatModel(m.chatModelHandler()).
+		Tool(m.toolHandler()).
+		Embedding(m.embeddingHandler()).
+		Retriever(m.retrieverHandler()).
+		Handler()
+}
+
+func (m *Metrics) chatModelHandler() *callbacksHelper.ModelCallbackHandler {
+	return &callbacksHelper.ModelCallbackHandler{
+		OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *model.CallbackInput) context.Context {
+			label := runInfoLabel(info)
+			if input != nil && input.Config != nil && input.Config.Model != "" {
+				label = input.Config.Model
+			}
+			return withState(ctx, label)
+		},
+		OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+			m.observeChat(stateFrom(ctx, info), output, output != nil && cacheHit(output.Extra))
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+			s := stateFrom(ctx, info)
+			go func() {
+				defer output.Close()
+				last := &model.CallbackOutput{}
+				hit := false
+				for {
+					chunk, err := output.Recv()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					if err != nil {
+						m.chatRequests.WithLabelValues(s.label, statusError).Inc()
+						return
+					}
+					if chunk == nil {
+						continue
+					}
+					hit = hit || cacheHit(chunk.Extra)
+					if chunk.TokenUsage != nil || messageUsage(chunk.Message) != nil {
+						last = chunk
+					}
+				}
+				m.observeChat(s, last, hit)
+			}()
+			return ctx
+		},
+		OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			s := stateFrom(ctx, info)
+			m.chatRequests.WithLabelValues(s.label, statusError).Inc()
+			m.chatLatency.WithLabelValues(s.label).Observe(time.Since(s.start).Seconds())
+			return ctx
+		},
+	}
+}
+
+// observeChat 记录一次成功的调用；缓存回放的是当初的用量，不再计入 token
+func (m *Metrics) observeChat(s *callState, output *model.CallbackOutput, cached bool) {
+	m.chatRequests.WithLabelValues(s.label, statusOK).Inc()
+	m.chatLatency.WithLabelValues(s.label).Observe(time.Since(s.start).Seconds())
+	if output == nil || cached {
+		return
+	}
+	var prompt, completion int
+	switch {
+	case output.TokenUsage != nil:
+		prompt, completion = output.TokenUsage.PromptTokens, output.TokenUsage.CompletionTokens
+	case messageUsage(output.Message) != nil:
+		usage := messageUsage(output.Message)
+		prompt, completion = usage.PromptTokens, usage.CompletionTokens
+	}
+	m.chatTokens.WithLabelValues(s.label, "prompt").Add(float64(prompt))
+	m.chatTokens.WithLabelValues(s.label, "completion").Add(float64(completion))
+}
+
+func cacheHit(extra map[string]any) bool {
+	hit, _ := extra[cache.ExtraKeyHit].(bool)
+	return hit
+}
+
+func messageUsage(msg *schema.Message) *schema.TokenUsage {
+	if msg == nil || msg.ResponseMeta == nil {
+		return nil
+	}
+	return msg.ResponseMeta.Usage
+}
+
+func (m *Metrics) toolHandler() *callbacksHelper.ToolCallbackHandler {
+	observe := func(s *callState, status string) {
+		m.toolCalls.WithLabelValues(s.label, status).Inc()
+		m.toolLatency.WithLabelValues(s.label).Observe(time.Since(s.start).Seconds())
+	}
+	return &callbacksHelper.ToolCallbackHandler{
+		OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *tool.CallbackInput) context.Context {
+			return withState(ctx, runInfoLabel(info))
+		},
+		OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *tool.CallbackOutput) context.Context {
+			observe(stateFrom(ctx, info), statusOK)
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*tool.CallbackOutput]) context.Context {
+			s := stateFrom(ctx, info)
+			go func() {
+				defer output.Close()
+				for {
+					_, err := output.Recv()
+					if errors.Is(err, io.EOF) {
+						observe(s, statusOK)
+						return
+					}
+					if err != nil {
+						observe(s, statusError)
+						return
+					}
+				}
+			}()
+			return ctx
+		},
+		OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			observe(stateFrom(ctx, info), statusError)
+			return ctx
+		},
+	}
+}
+
+func (m *Metrics) embeddingHandler() *callbacksHelper.EmbeddingCallbackHandler {
+	return &callbacksHelper.EmbeddingCallbackHandler{
+		OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *embedding.CallbackInput) context.Context {
+			label := runInfoLabel(info)
+			if input != nil && input.Config != nil && input.Config.Model != "" {
+				label = input.Config.Model
+			}
+			if input != nil {
+				m.embedBatch.WithLabelValues(label).Observe(float64(len(input.Texts)))
+			}
+			return withState(ctx, label)
+		},
+		OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *embedding.CallbackOutput) context.Context {
+			s := stateFrom(ctx, info)
+			m.embedRequests.WithLabelValues(s.label, statusOK).Inc()
+			if output != nil && output.TokenUsage != nil {
+				m.embedTokens.WithLabelValues(s.label).Add(float64(output.TokenUsage.TotalTokens))
+			}
+			return ctx
+		},
+		OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			m.embedRequests.WithLabelValues(stateFrom(ctx, info).label, statusError).Inc()
+			return ctx
+		},
+	}
+}
+
+func (m *Metrics) retrieverHandler() *callbacksHelper.RetrieverCallbackHandler {
+	return &callbacksHelper.RetrieverCallbackHandler{
+		OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *retriever.CallbackInput) context.Context {
+			return withState(ctx, runInfoLabel(info))
+		},
+		OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *retriever.CallbackOutput) context.Context {
+			s := stateFrom(ctx, info)
+			m.retrieveCalls.WithLabelValues(s.label, statusOK).Inc()
+			m.retrieveTime.WithLabelValues(s.label).Observe(time.Since(s.start).Seconds())
+			hits := 0
+			if output != nil {
+				hits = len(output.Docs)
+			}
+			m.retrieveHits.WithLabelValues(s.label).Observe(float64(hits))
+			return ctx
+		},
+		OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			s := stateFrom(ctx, info)
+			m.retrieveCalls.WithLabelValues(s.label, statusError).Inc()
+			m.retrieveTime.WithLabelValues(s.label).Observe(time.Since(s.start).Seconds())
+			return ctx
+		},
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// EnvAddr 设置后（如 :9090），实验进程通过 ServeFromEnv 在该地址暴露 /metrics
+	EnvAddr = "EINOX_METRICS_ADDR"
+	// EnvLinger 进程结束前继续提供 /metrics 的时长，默认 15s，保证短进程至少被抓取一次
+	EnvLinger = "EINOX_METRICS_LINGER"
+)
+
+// Server 进程内的指标服务
+type Server struct {
+	// Registry 可以继续注册限流器等采集器
+	Registry *prometheus.Registry
+	Metrics  *Metrics
+	srv      *http.Server
+	linger   time.Duration
+}
+
+// Serve 把指标 callbacks 注册为全局 handler，并在 addr 上异步暴露 /metrics
+// 当前进程里所有 Eino 组件调用都会被采集；监听失败时直接返回错误
+func Serve(addr string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	reg := NewRegistry()
+	m := New(reg)
+	callbacks.AppendGlobalHandlers(m.Handler())
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", HTTPHandler(reg))
+	s := &Server{
+		Registry: reg,
+		Metrics:  m,
+		srv:      &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second},
+		linger:   15 * time.Second,
+	}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("[metrics] %v", err)
+		}
+	}()
+	log.Printf("[metrics] 指标已暴露: http://%s/metrics", ln.Addr())
+	return s, nil
+}
+
+// ServeFromEnv 读取 EINOX_METRICS_ADDR 启动指标服务，未设置时返回 nil, nil
+// 返回的 *Server 为 nil 时调用 Close 是安全的
+func ServeFromEnv() (*Server, error) {
+	addr := os.Getenv(EnvAddr)
+	if addr == "" {
+		return nil, nil
+	}
+	s, err := Serve(addr)
+	if err != nil {
+		return nil, err
+	}
+	if v := os.Getenv(EnvLinger); v != "" {
+		if s.linger, err = time.ParseDuration(v); err != nil {
+			_ = s.srv.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Close 再保留 linger 时长供抓取，然后关闭 HTTP 服务
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	if s.linger > 0 {
+		log.Printf("[metrics] %s 后关闭指标服务", s.linger)
+		time.Sleep(s.linger)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
@@ -0,0 +1,95 @@
+package parsers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// CSVConfig CSV 解析配置
+type CSVConfig struct {
+	TableConfig
+	// Comma 分隔符，为 0 时根据第一行在 , ; \t | 中自动选择
+	Comma rune
+}
+
+// CSVParser 解析 CSV / TSV：按行或整表输出文档，见 TableConfig
+type CSVParser struct {
+	cfg CSVConfig
+}
+
+var _ parser.Parser = (*CSVParser)(nil)
+
+// NewCSVParser 创建 CSV 解析器
+func NewCSVParser(ctx context.Context, cfg *CSVConfig) (*CSVParser, error) {
+	if cfg == nil {
+		cfg = &CSVConfig{}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &CSVParser{cfg: *cfg}, nil
+}
+
+func (p *CSVParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	option := parser.GetCommonOptions(&parser.Options{}, opts...)
+	br := bufio.NewReader(reader)
+	// 去掉 Excel 导出时常见的 UTF-8 BOM
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte{0xEF, 0xBB, 0xBF}) {
+		_, _ = br.Discard(3)
+	}
+	comma := p.cfg.Comma
+	if comma == 0 {
+		line, _ := br.Peek(4096)
+		comma = sniffComma(line)
+	}
+
+	r := csv.NewReader(br)
+	r.Comma = comma
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	var rows []tableRow
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, tableRow{num: line, cells: rec})
+	}
+	return p.cfg.documents([]table{{rows: rows}}, option.URI, option.ExtraMeta)
+}
+
+// sniffComma 统计第一行（引号外）各候选分隔符的出现次数，取最多的一个
+func sniffComma(b []byte) rune {
+	if i := bytes.IndexByte(b, '\n'); i >= 0 {
+		b = b[:i]
+	}
+	counts := map[byte]int{}
+	quoted := false
+	for _, c := range b {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case !quoted:
+			counts[c]++
+		}
+	}
+	best := byte(',')
+	for _, c := range []byte{';', '\t', '|'} {
+		if counts[c] > counts[best] {
+			best = c
+		}
+	}
+	return rune(best)
+}
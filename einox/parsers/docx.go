@@ -0,0 +1,374 @@
+package parsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DOCX 文档属性（docProps/core.xml）对应的元数据 key，时间保持原样（W3CDTF，即 RFC3339）
+const (
+	MetaKeyAuthor         = "_author"
+	MetaKeySubject        = "_subject"
+	MetaKeyKeywords       = "_keywords"
+	MetaKeyLastModifiedBy = "_last_modified_by"
+	MetaKeyCreated        = "_created"
+	MetaKeyModified       = "_modified"
+)
+
+// DocxConfig DOCX 解析配置
+type DocxConfig struct {
+	// MaxPartSize 单个 XML 部件解压后的大小上限，默认 64MB
+	MaxPartSize int64
+}
+
+// DocxParser 纯 Go 解析 .docx：标题转为 #，列表转为 - / 1.，表格转为 Markdown 表格，文档属性写入 MetaData
+// 不依赖 Word 或 LibreOffice；图片、文本框、页眉页脚与批注会被忽略
+type DocxParser struct {
+	maxPartSize int64
+}
+
+var _ parser.Parser = (*DocxParser)(nil)
+
+// NewDocxParser 创建 DOCX 解析器
+func NewDocxParser(ctx context.Context, cfg *DocxConfig) (*DocxParser, error) {
+	if cfg == nil {
+		cfg = &DocxConfig{}
+	}
+	p := &DocxParser{maxPartSize: cfg.MaxPartSize}
+	if p.maxPartSize <= 0 {
+		p.maxPartSize = defaultMaxPartSize
+	}
+	return p, nil
+}
+
+// Parse 整个文档输出为一个 Document
+func (p *DocxParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	option := parser.GetCommonOptions(&parser.Options{}, opts...)
+	zr, err := openZip(reader)
+	if err != nil {
+		return nil, fmt.Errorf("docx: %w", err)
+	}
+	read := func(name string) (*xnode, error) {
+		b, err := readPart(zr, name, p.maxPartSize)
+		if err != nil || b == nil {
+			return nil, err
+		}
+		return parseXML(b)
+	}
+
+	body, err := read("word/document.xml")
+	if err != nil {
+		return nil, fmt.Errorf("docx: document.xml: %w", err)
+	}
+	if body == nil {
+		return nil, errors.New("docx: word/document.xml not found")
+	}
+	styles, err := read("word/styles.xml")
+	if err != nil {
+		return nil, fmt.Errorf("docx: styles.xml: %w", err)
+	}
+	numbering, err := read("word/numbering.xml")
+	if err != nil {
+		return nil, fmt.Errorf("docx: numbering.xml: %w", err)
+	}
+	core, err := read("docProps/core.xml")
+	if err != nil {
+		return nil, fmt.Errorf("docx: core.xml: %w", err)
+	}
+
+	r := &docxRenderer{
+		styles:   parseDocxStyles(styles),
+		formats:  parseDocxNumbering(numbering),
+		counters: map[string][]int{},
+	}
+	r.blocks(body.path("document", "body"))
+	return []*schema.Document{{
+		Content:  r.String(),
+		MetaData: mergeMeta(docxProperties(core), option.URI, option.ExtraMeta),
+	}}, nil
+}
+
+// docxProperties 读取 core.xml 中的标题、作者、修改时间等属性
+func docxProperties(core *xnode) map[string]any {
+	keys := map[string]string{
+		"title":          MetaKeyTitle,
+		"subject":        MetaKeySubject,
+		"creator":        MetaKeyAuthor,
+		"keywords":       MetaKeyKeywords,
+		"description":    MetaKeyDesc,
+		"lastModifiedBy": MetaKeyLastModifiedBy,
+		"created":        MetaKeyCreated,
+		"modified":       MetaKeyModified,
+	}
+	meta := map[string]any{}
+	for _, n := range core.path("coreProperties").children {
+		if key, ok := keys[n.name]; ok {
+			if v := strings.TrimSpace(n.text); v != "" {
+				meta[key] = v
+			}
+		}
+	}
+	return meta
+}
+
+// docxStyle 段落样式中与结构有关的部分
+type docxStyle struct {
+	name    string
+	basedOn string
+	outline int // outlineLvl + 1，0 表示未设置
+	numID   string
+	ilvl    int
+}
+
+var headingStyleRe = regexp.MustCompile(`(?i)^heading\s*([1-9])$`)
+
+func parseDocxStyles(root *xnode) map[string]*docxStyle {
+	styles := map[string]*docxStyle{}
+	for _, s := range root.path("styles").children {
+		if s.name != "style" || s.attr("type") != "paragraph" {
+			continue
+		}
+		st := &docxStyle{
+			name:    s.path("name").attr("val"),
+			basedOn: s.path("basedOn").attr("val"),
+			numID:   s.path("pPr", "numPr", "numId").attr("val"),
+		}
+		if lvl, err := strconv.Atoi(s.path("pPr", "outlineLvl").attr("val")); err == nil && lvl < 9 {
+			st.outline = lvl + 1
+		}
+		st.ilvl, _ = strconv.Atoi(s.path("pPr", "numPr", "ilvl").attr("val"))
+		styles[s.attr("styleId")] = st
+	}
+	return styles
+}
+
+// parseDocxNumbering numId → 各级是否为有序列表
+func parseDocxNumbering(root *xnode) map[string]map[int]bool {
+	abstract := map[string]map[int]bool{}
+	for _, a := range root.path("numbering").children {
+		if a.name != "abstractNum" {
+			continue
+		}
+		levels := map[int]bool{}
+		for _, l := range a.children {
+			if l.name != "lvl" {
+				continue
+			}
+			ilvl, _ := strconv.Atoi(l.attr("ilvl"))
+			f := l.path("numFmt").attr("val")
+			levels[ilvl] = f != "" && f != "bullet" && f != "none"
+		}
+		abstract[a.attr("abstractNumId")] = levels
+	}
+	nums := map[string]map[int]bool{}
+	for _, n := range root.path("numbering").children {
+		if n.name == "num" {
+			nums[n.attr("numId")] = abstract[n.path("abstractNumId").attr("val")]
+		}
+	}
+	return nums
+}
+
+type docxRenderer struct {
+	styles  map[string]*docxStyle
+	formats map[string]map[int]bool
+	// counters 每个 numId 各级的当前序号
+	counters map[string][]int
+	out      strings.Builder
+	lastList bool
+}
+
+func (r *docxRenderer) String() string {
+	return strings.TrimSpace(r.out.String())
+}
+
+// emit 写入一个块；相邻的列表项之间不空行
+func (r *docxRenderer) emit(text string, list bool) {
+	if r.out.Len() > 0 {
+		if list && r.lastList {
+			r.out.WriteString("\n")
+		} else {
+			r.out.WriteString("\n\n")
+		}
+	}
+	r.out.WriteString(text)
+	r.lastList = list
+}
+
+// blocks 渲染 body / 内容控件中的段落与表格
+func (r *docxRenderer) blocks(n *xnode) {
+	if n == nil {
+		return
+	}
+	for _, c := range n.children {
+		switch c.name {
+		case "p":
+			r.paragraph(c)
+		case "tbl":
+			if t := r.table(c); t != "" {
+				r.emit(t, false)
+			}
+		case "sdt":
+			r.blocks(c.child("sdtContent"))
+		case "customXml", "ins":
+			r.blocks(c)
+		}
+	}
+}
+
+func (r *docxRenderer) paragraph(p *xnode) {
+	text := strings.TrimSpace(runText(p))
+	if text == "" {
+		return
+	}
+	styleID := p.path("pPr", "pStyle").attr("val")
+
+	if level := r.headingLevel(p, styleID); level > 0 {
+		r.emit(strings.Repeat("#", level)+" "+strings.Join(strings.Fields(text), " "), false)
+		return
+	}
+
+	numID, ilvl := p.path("pPr", "numPr", "numId").attr("val"), p.path("pPr", "numPr", "ilvl").attr("val")
+	level, _ := strconv.Atoi(ilvl)
+	if numID == "" {
+		if st := r.style(styleID, func(s *docxStyle) bool { return s.numID != "" }); st != nil {
+			numID = st.numID
+			if ilvl == "" {
+				level = st.ilvl
+			}
+		}
+	}
+	if numID == "" || numID == "0" {
+		r.emit(text, false)
+		return
+	}
+
+	level = min(max(level, 0), 8)
+	indent := strings.Repeat("  ", level)
+	item := strings.ReplaceAll(text, "\n", "\n"+indent+"  ")
+	if !r.formats[numID][level] {
+		r.emit(indent+"- "+item, true)
+		return
+	}
+	// 同一列表进入上一级时，下级序号重新开始
+	cs := r.counters[numID]
+	if len(cs) < level+1 {
+		cs = append(cs, make([]int, level+1-len(cs))...)
+	}
+	cs[level]++
+	for i := level + 1; i < len(cs); i++ {
+		cs[i] = 0
+	}
+	r.counters[numID] = cs
+	r.emit(fmt.Sprintf("%s%d. %s", indent, cs[level], item), true)
+}
+
+// headingLevel 段落自身的大纲级别优先，其次沿样式继承链查找大纲级别或 Title / heading N 样式名
+// 中文版 Word 的标题样式 ID 是 "1"、"2"，所以不能只看样式 ID
+func (r *docxRenderer) headingLevel(p *xnode, styleID string) int {
+	if lvl, err := strconv.Atoi(p.path("pPr", "outlineLvl").attr("val")); err == nil {
+		if lvl < 9 {
+			return lvl + 1
+		}
+		return 0
+	}
+	if st := r.style(styleID, func(s *docxStyle) bool { return s.outline > 0 }); st != nil {
+		return st.outline
+	}
+	for id, depth := styleID, 0; id != "" && depth < 10; depth++ {
+		name := id
+		st := r.styles[id]
+		if st != nil && st.name != "" {
+			name = st.name
+		}
+		if strings.EqualFold(name, "title") {
+			return 1
+		}
+		if m := headingStyleRe.FindStringSubmatch(name); m != nil {
+			return int(m[1][0] - '0')
+		}
+		if st == nil {
+			break
+		}
+		id = st.basedOn
+	}
+	return 0
+}
+
+// style 沿 basedOn 链找到第一个满足条件的样式
+func (r *docxRenderer) style(id string, ok func(*docxStyle) bool) *docxStyle {
+	for depth := 0; id != "" && depth < 10; depth++ {
+		st := r.styles[id]
+		if st == nil {
+			return nil
+		}
+		if ok(st) {
+			return st
+		}
+		id = st.basedOn
+	}
+	return nil
+}
+
+// table 转为 Markdown 表格，第一行作为表头；合并单元格按跨列数补空单元格
+func (r *docxRenderer) table(tbl *xnode) string {
+	var rows [][]string
+	for _, tr := range tbl.children {
+		if tr.name != "tr" {
+			continue
+		}
+		var row []string
+		for _, tc := range tr.children {
+			if tc.name != "tc" {
+				continue
+			}
+			var lines []string
+			for _, p := range tc.all("p") {
+				if t := strings.TrimSpace(runText(p)); t != "" {
+					lines = append(lines, t)
+				}
+			}
+			row = append(row, strings.Join(lines, "\n"))
+			if span, _ := strconv.Atoi(tc.path("tcPr", "gridSpan").attr("val")); span > 1 {
+				row = append(row, make([]string, span-1)...)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return markdownTable(rows)
+}
+
+// runText 提取段落文本；跳过已删除的修订、域代码和图形
+func runText(n *xnode) string {
+	var b strings.Builder
+	var visit func(*xnode)
+	visit = func(x *xnode) {
+		for _, c := range x.children {
+			switch c.name {
+			case "t":
+				b.WriteString(c.text)
+			case "tab":
+				b.WriteString("\t")
+			case "br", "cr":
+				if c.attr("type") != "page" {
+					b.WriteString("\n")
+				}
+			case "noBreakHyphen":
+				b.WriteString("-")
+			case "pPr", "rPr", "del", "delText", "instrText", "drawing", "pict", "AlternateContent", "tbl":
+			default:
+				visit(c)
+			}
+		}
+	}
+	visit(n)
+	return b.String()
+}
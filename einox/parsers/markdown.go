@@ -0,0 +1,359 @@
+package parsers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Markdown 解析器写入的元数据 key；front matter 中的字段按原名写入 MetaData
+const (
+	// MetaKeyOutline 全文标题大纲，[]Heading
+	MetaKeyOutline = "_outline"
+	// MetaKeyCodeBlocks 围栏代码块，[]CodeBlock
+	MetaKeyCodeBlocks = "_code_blocks"
+	// MetaKeyHeadingPath 按标题切分时，当前小节的标题路径（从一级标题开始），[]string
+	MetaKeyHeadingPath = "_heading_path"
+	// MetaKeyHeadingLevel 按标题切分时，当前小节的标题级别，前言为 0
+	MetaKeyHeadingLevel = "_heading_level"
+)
+
+// Heading 一个标题
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	// Path 从一级标题到当前标题的路径，包含当前标题
+	Path []string `json:"path"`
+	// Line 在正文（去掉 front matter 后）中的行号，从 1 开始
+	Line int `json:"line"`
+}
+
+// CodeBlock 一个围栏代码块
+type CodeBlock struct {
+	// Language 信息串的第一个词，小写；没有标注时为空
+	Language string `json:"language"`
+	// Path 代码块所在小节的标题路径
+	Path []string `json:"path"`
+	Line int      `json:"line"`
+	// Lines 代码行数，不含围栏
+	Lines int `json:"lines"`
+}
+
+// MarkdownConfig Markdown 解析配置
+type MarkdownConfig struct {
+	// SplitSections 按标题切分为多个文档，每个文档带上标题路径和本节的代码块；只有标题的小节不输出，
+	// 除非全文都是这样的小节；默认整篇输出一个文档，可再交给 eino-ext 的 markdown.HeaderSplitter 切分
+	SplitSections bool
+	// MaxSectionLevel 只在不超过该级别的标题处切分，更深的标题留在小节正文中；默认 6
+	MaxSectionLevel int
+}
+
+// MarkdownParser 解析 Markdown：front matter（YAML --- 或 TOML +++）提取到 MetaData 并从正文中移除，
+// 记录标题大纲与围栏代码块的语言；能识别 Setext 标题，代码块内以 # 开头的行不会被当作标题
+type MarkdownParser struct {
+	split    bool
+	maxLevel int
+}
+
+var _ parser.Parser = (*MarkdownParser)(nil)
+
+// NewMarkdownParser 创建 Markdown 解析器
+func NewMarkdownParser(ctx context.Context, cfg *MarkdownConfig) (*MarkdownParser, error) {
+	if cfg == nil {
+		cfg = &MarkdownConfig{}
+	}
+	p := &MarkdownParser{split: cfg.SplitSections, maxLevel: cfg.MaxSectionLevel}
+	if p.maxLevel <= 0 || p.maxLevel > 6 {
+		p.maxLevel = 6
+	}
+	return p, nil
+}
+
+func (p *MarkdownParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	option := parser.GetCommonOptions(&parser.Options{}, opts...)
+	b, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	text := strings.ReplaceAll(string(bytes.TrimPrefix(b, []byte("\xef\xbb\xbf"))), "\r\n", "\n")
+	front, body := splitFrontMatter(text)
+	lines := strings.Split(body, "\n")
+	outline, codes := scanMarkdown(lines)
+
+	base := func() map[string]any {
+		meta := make(map[string]any, len(front)+2)
+		for k, v := range front {
+			meta[k] = v
+		}
+		if title := markdownTitle(front, outline); title != "" {
+			meta[MetaKeyTitle] = title
+		}
+		return mergeMeta(meta, option.URI, option.ExtraMeta)
+	}
+
+	if !p.split {
+		meta := base()
+		meta[MetaKeyOutline] = outline
+		meta[MetaKeyCodeBlocks] = codes
+		return []*schema.Document{{Content: strings.TrimSpace(body), MetaData: meta}}, nil
+	}
+
+	// 按标题切分：每个切分点到下一个切分点之间为一个小节，标题之前的内容作为前言
+	var (
+		docs    []*schema.Document
+		starts  = []Heading{{Line: 1}}
+		section = func(h Heading, end int, keepEmpty bool) {
+			// 只有标题行的小节默认不输出；Setext 标题占两行
+			from := h.Line - 1
+			if h.Level > 0 && !keepEmpty {
+				from++
+				if _, _, ok := atxHeading(lines[h.Line-1]); !ok {
+					from++
+				}
+			}
+			if strings.TrimSpace(strings.Join(lines[from:end], "\n")) == "" {
+				return
+			}
+			meta := base()
+			meta[MetaKeyHeadingPath] = h.Path
+			meta[MetaKeyHeadingLevel] = h.Level
+			var own []CodeBlock
+			for _, c := range codes {
+				if c.Line >= h.Line && c.Line <= end {
+					own = append(own, c)
+				}
+			}
+			meta[MetaKeyCodeBlocks] = own
+			docs = append(docs, &schema.Document{
+				Content:  strings.TrimSpace(strings.Join(lines[h.Line-1:end], "\n")),
+				MetaData: meta,
+			})
+		}
+	)
+	for _, h := range outline {
+		if h.Level <= p.maxLevel {
+			starts = append(starts, h)
+		}
+	}
+	split := func(keepEmpty bool) {
+		for i, h := range starts {
+			end := len(lines)
+			if i+1 < len(starts) {
+				end = starts[i+1].Line - 1
+			}
+			section(h, end, keepEmpty)
+		}
+	}
+	split(false)
+	// 全部小节都只有标题时（如只写了提纲）保留这些标题，不让整个文件没有输出
+	if len(docs) == 0 {
+		split(true)
+	}
+	return docs, nil
+}
+
+// markdownTitle front matter 中的 title 优先，其次第一个一级标题
+func markdownTitle(front map[string]any, outline []Heading) string {
+	if t, ok := front["title"].(string); ok && strings.TrimSpace(t) != "" {
+		return strings.TrimSpace(t)
+	}
+	for _, h := range outline {
+		if h.Level == 1 {
+			return h.Text
+		}
+	}
+	return ""
+}
+
+// splitFrontMatter 识别文件开头的 YAML（---）或 TOML（+++）front matter；
+// 解析失败或结果不是键值对时视为普通正文（--- 也可能只是分隔线）
+func splitFrontMatter(text string) (map[string]any, string) {
+	var delim string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delim = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delim = "+++"
+	default:
+		return nil, text
+	}
+	rest := text[len(delim)+1:]
+	var raw string
+	for i := 0; ; {
+		j := strings.IndexByte(rest[i:], '\n')
+		line := rest[i:]
+		if j >= 0 {
+			line = rest[i : i+j]
+		}
+		if strings.TrimRight(line, " \t") == delim || delim == "---" && strings.TrimRight(line, " \t") == "..." {
+			raw = rest[:i]
+			if j < 0 {
+				rest = ""
+			} else {
+				rest = rest[i+j+1:]
+			}
+			break
+		}
+		if j < 0 {
+			return nil, text
+		}
+		i += j + 1
+	}
+
+	front := map[string]any{}
+	var err error
+	if delim == "---" {
+		err = yaml.Unmarshal([]byte(raw), &front)
+	} else {
+		err = toml.Unmarshal([]byte(raw), &front)
+	}
+	if err != nil {
+		return nil, text
+	}
+	return front, rest
+}
+
+// scanMarkdown 逐行扫描标题与围栏代码块；代码块内的内容不参与标题识别
+func scanMarkdown(lines []string) ([]Heading, []CodeBlock) {
+	var (
+		outline []Heading
+		codes   []CodeBlock
+		path    []string
+		levels  []int
+		fence   string // 当前代码块的开始围栏，为空表示不在代码块内
+		code    *CodeBlock
+	)
+	push := func(level int, text string, line int) {
+		for len(levels) > 0 && levels[len(levels)-1] >= level {
+			levels, path = levels[:len(levels)-1], path[:len(path)-1]
+		}
+		levels, path = append(levels, level), append(path, text)
+		outline = append(outline, Heading{Level: level, Text: text, Path: append([]string(nil), path...), Line: line})
+	}
+
+	for i, line := range lines {
+		if fence != "" {
+			if isClosingFence(line, fence) {
+				fence = ""
+				codes = append(codes, *code)
+			} else {
+				code.Lines++
+			}
+			continue
+		}
+		if f, lang, ok := openingFence(line); ok {
+			fence = f
+			code = &CodeBlock{Language: lang, Path: append([]string(nil), path...), Line: i + 1}
+			continue
+		}
+		if level, text, ok := atxHeading(line); ok {
+			push(level, text, i+1)
+			continue
+		}
+		// Setext 标题：非空段落行下面紧跟 === 或 ---
+		if level := isSetextUnderline(line); level > 0 && i > 0 && isParagraphLine(lines[i-1]) &&
+			(i < 2 || strings.TrimSpace(lines[i-2]) == "" || len(outline) > 0 && outline[len(outline)-1].Line == i-1) {
+			push(level, strings.TrimSpace(lines[i-1]), i)
+		}
+	}
+	if fence != "" {
+		// 未闭合的代码块延续到文末
+		codes = append(codes, *code)
+	}
+	return outline, codes
+}
+
+// atxHeading 识别 # 标题：最多 3 个空格缩进，1-6 个 #，后跟空格或行尾；去掉结尾的 # 序列
+func atxHeading(line string) (int, string, bool) {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return 0, "", false
+	}
+	level := 0
+	for level < len(s) && s[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level < len(s) && s[level] != ' ' && s[level] != '\t' {
+		return 0, "", false
+	}
+	text := strings.TrimSpace(s[level:])
+	if t := strings.TrimRight(text, "#"); t != text && (t == "" || strings.HasSuffix(t, " ")) {
+		text = strings.TrimSpace(t)
+	}
+	return level, text, true
+}
+
+// isSetextUnderline === 返回 1，--- 返回 2，否则 0
+func isSetextUnderline(line string) int {
+	s := strings.TrimSpace(line)
+	if s == "" || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return 0
+	}
+	switch {
+	case strings.Trim(s, "=") == "":
+		return 1
+	case strings.Trim(s, "-") == "":
+		return 2
+	}
+	return 0
+}
+
+// isParagraphLine 可以作为 Setext 标题文字的行：非空，且不是标题、列表、引用、表格或代码缩进
+func isParagraphLine(line string) bool {
+	s := strings.TrimSpace(line)
+	if s == "" || len(line)-len(strings.TrimLeft(line, " ")) > 3 {
+		return false
+	}
+	if _, _, ok := atxHeading(line); ok {
+		return false
+	}
+	switch s[0] {
+	case '-', '*', '+', '>', '|', '=':
+		return false
+	}
+	return true
+}
+
+// openingFence 识别 ``` 或 ~~~ 开始的围栏，返回围栏与语言
+func openingFence(line string) (string, string, bool) {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 || len(s) < 3 || s[0] != '`' && s[0] != '~' {
+		return "", "", false
+	}
+	n := 0
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	if n < 3 {
+		return "", "", false
+	}
+	info := strings.TrimSpace(s[n:])
+	// 反引号围栏的信息串中不能再有反引号，否则是行内代码
+	if s[0] == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+	lang := ""
+	if f := strings.Fields(info); len(f) > 0 {
+		lang = strings.ToLower(strings.Trim(f[0], "{}."))
+	}
+	return s[:n], lang, true
+}
+
+// isClosingFence 同一字符、长度不小于开始围栏，后面只能有空白
+func isClosingFence(line, fence string) bool {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return false
+	}
+	n := 0
+	for n < len(s) && s[n] == fence[0] {
+		n++
+	}
+	return n >= len(fence) && strings.TrimSpace(s[n:]) == ""
+}
@@ -0,0 +1,192 @@
+package parsers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// defaultMaxPartSize 单个 XML 部件解压后的默认上限，防止压缩炸弹
+const defaultMaxPartSize = 64 << 20
+
+// openZip DOCX / XLSX 都是 zip 包，读入内存后打开
+func openZip(r io.Reader) (*zip.Reader, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(bytes.NewReader(b), int64(len(b)))
+}
+
+// readPart 读取包内的一个部件，不存在时返回 nil, nil
+func readPart(zr *zip.Reader, name string, limit int64) ([]byte, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		b, err := io.ReadAll(io.LimitReader(rc, limit+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(b)) > limit {
+			return nil, fmt.Errorf("%s exceeds %d bytes", name, limit)
+		}
+		return b, nil
+	}
+	return nil, nil
+}
+
+// xnode 简化的 XML 节点，只保留本地名、属性、子节点与文本
+type xnode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xnode
+	text     string
+}
+
+// parseXML 把整个部件解析成节点树；OOXML 部件不大，树形遍历比流式状态机好写
+func parseXML(b []byte) (*xnode, error) {
+	dec := xml.NewDecoder(bytes.NewReader(b))
+	root := &xnode{}
+	stack := []*xnode{root}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		cur := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xnode{name: t.Name.Local, attrs: t.Attr}
+			cur.children = append(cur.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			cur.text += string(t)
+		}
+	}
+	return root, nil
+}
+
+// attr 按本地名取属性，忽略命名空间前缀
+func (n *xnode) attr(name string) string {
+	if n == nil {
+		return ""
+	}
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// child 第一个指定名称的子节点
+func (n *xnode) child(name string) *xnode {
+	if n == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// path 依次取子节点，如 n.path("pPr", "numPr", "ilvl")
+func (n *xnode) path(names ...string) *xnode {
+	for _, name := range names {
+		n = n.child(name)
+	}
+	return n
+}
+
+// value 节点文本，节点不存在时为空
+func (n *xnode) value() string {
+	if n == nil {
+		return ""
+	}
+	return n.text
+}
+
+// all 按文档顺序收集所有指定名称的后代节点
+func (n *xnode) all(name string) []*xnode {
+	var out []*xnode
+	var visit func(*xnode)
+	visit = func(x *xnode) {
+		for _, c := range x.children {
+			if c.name == name {
+				out = append(out, c)
+			}
+			visit(c)
+		}
+	}
+	if n != nil {
+		visit(n)
+	}
+	return out
+}
+
+// mergeMeta 按 eino-ext 解析器的约定合并元数据：_source 取 URI，ExtraMeta 覆盖解析出的字段
+func mergeMeta(meta map[string]any, uri string, extra map[string]any) map[string]any {
+	if meta == nil {
+		meta = map[string]any{}
+	}
+	meta[MetaKeySource] = uri
+	for k, v := range extra {
+		meta[k] = v
+	}
+	return meta
+}
+
+// escapeCell Markdown 表格单元格转义
+func escapeCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// markdownTable 第一行作为表头，列数按最宽的行补齐
+func markdownTable(rows [][]string) string {
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return ""
+	}
+	var b strings.Builder
+	writeRow := func(r []string) {
+		b.WriteString("|")
+		for i := 0; i < width; i++ {
+			cell := ""
+			if i < len(r) {
+				cell = escapeCell(r[i])
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	writeRow(rows[0])
+	b.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+	for _, r := range rows[1:] {
+		writeRow(r)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
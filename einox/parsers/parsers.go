@@ -0,0 +1,9 @@
+// Package parsers 补充 eino-ext 没有覆盖的文档格式解析器，均实现 parser.Parser，可直接注册到 parser.ExtParser
+package parsers
+
+// 与 eino-ext 解析器一致的元数据 key
+const (
+	MetaKeySource = "_source"
+	MetaKeyTitle  = "_title"
+	MetaKeyDesc   = "_description"
+)
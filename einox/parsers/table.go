@@ -0,0 +1,387 @@
+package parsers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// 表格解析器写入的元数据 key
+const (
+	// MetaKeySheet 工作表名，CSV 没有
+	MetaKeySheet = "_sheet"
+	// MetaKeyRow 数据行在原文件中的行号（从 1 开始）
+	MetaKeyRow = "_row"
+	// MetaKeyRows 表格文档包含的数据行数
+	MetaKeyRows = "_rows"
+	// MetaKeyColumns 列名，[]string
+	MetaKeyColumns = "_columns"
+	// MetaKeyColumnTypes 列名 → 推断出的类型（int / float / bool / string）
+	MetaKeyColumnTypes = "_column_types"
+)
+
+// TableMode 表格如何切成文档
+type TableMode string
+
+const (
+	// TableModeRow 每个数据行一个文档，适合 FAQ、商品目录
+	TableModeRow TableMode = "row"
+	// TableModeSheet 每个工作表一个文档，内容为 Markdown 表格
+	TableModeSheet TableMode = "sheet"
+)
+
+// HeaderMode 第一行是否为表头
+type HeaderMode string
+
+const (
+	// HeaderAuto 自动判断：第一行各列非空、不重复、都不是数字，且与后面的数据行类型或长度明显不同
+	HeaderAuto HeaderMode = ""
+	HeaderYes  HeaderMode = "yes"
+	// HeaderNo 没有表头时列名为 A、B、C…
+	HeaderNo HeaderMode = "no"
+)
+
+// TableConfig CSV 与 XLSX 共用的配置
+type TableConfig struct {
+	// Mode 默认 TableModeRow
+	Mode   TableMode
+	Header HeaderMode
+	// ContentColumns 行模式下拼进 Content 的列，为空表示全部列；其余列按推断出的类型写入 MetaData
+	ContentColumns []string
+	// RowsPerDoc 表格模式下每个文档最多包含的数据行数，超出时拆成多个文档并重复表头；0 表示不拆
+	RowsPerDoc int
+	// DisableTypeInference 关闭类型推断，MetaData 中的值都保留为字符串
+	DisableTypeInference bool
+}
+
+func (c *TableConfig) validate() error {
+	switch c.Mode {
+	case "", TableModeRow, TableModeSheet:
+	default:
+		return fmt.Errorf("unknown table mode %q", c.Mode)
+	}
+	switch c.Header {
+	case HeaderAuto, HeaderYes, HeaderNo:
+	default:
+		return fmt.Errorf("unknown header mode %q", c.Header)
+	}
+	if c.RowsPerDoc < 0 {
+		return errors.New("rows per doc must not be negative")
+	}
+	return nil
+}
+
+// table 一张待输出的表：空行已去掉，行号保留原值
+type table struct {
+	sheet string
+	rows  []tableRow
+}
+
+type tableRow struct {
+	num   int
+	cells []string
+}
+
+// column 列名与推断出的类型
+type column struct {
+	name string
+	kind string
+}
+
+func (c *TableConfig) documents(tables []table, uri string, extra map[string]any) ([]*schema.Document, error) {
+	var docs []*schema.Document
+	for _, t := range tables {
+		t.rows = trimTable(t.rows)
+		if len(t.rows) == 0 {
+			continue
+		}
+		cols, data := c.columns(t.rows)
+		var (
+			out []*schema.Document
+			err error
+		)
+		if c.Mode == TableModeSheet {
+			out = c.sheetDocs(t.sheet, cols, data)
+		} else if out, err = c.rowDocs(t.sheet, cols, data); err != nil {
+			return nil, err
+		}
+		for _, d := range out {
+			d.MetaData = mergeMeta(d.MetaData, uri, extra)
+			docs = append(docs, d)
+		}
+	}
+	return docs, nil
+}
+
+// columns 确定列名并按列推断类型，返回数据行
+func (c *TableConfig) columns(rows []tableRow) ([]column, []tableRow) {
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r.cells))
+	}
+	header := c.Header == HeaderYes || c.Header == HeaderAuto && detectHeader(rows)
+	data := rows
+	cols := make([]column, width)
+	seen := map[string]int{}
+	for i := range cols {
+		name := ""
+		if header && i < len(rows[0].cells) {
+			name = strings.TrimSpace(rows[0].cells[i])
+		}
+		if name == "" {
+			name = columnLetter(i)
+		}
+		if seen[name]++; seen[name] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[name])
+		}
+		cols[i].name = name
+	}
+	if header {
+		data = rows[1:]
+	}
+	for i := range cols {
+		cols[i].kind = "string"
+		if !c.DisableTypeInference {
+			cols[i].kind = columnKind(data, i)
+		}
+	}
+	return cols, data
+}
+
+func (c *TableConfig) rowDocs(sheet string, cols []column, data []tableRow) ([]*schema.Document, error) {
+	content := map[int]bool{}
+	for _, name := range c.ContentColumns {
+		idx := -1
+		for i, col := range cols {
+			if col.name == name {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			names := make([]string, len(cols))
+			for i, col := range cols {
+				names[i] = col.name
+			}
+			return nil, fmt.Errorf("content column %q not found (columns: %s)", name, strings.Join(names, ", "))
+		}
+		content[idx] = true
+	}
+
+	var docs []*schema.Document
+	for _, r := range data {
+		meta := map[string]any{MetaKeyRow: r.num}
+		if sheet != "" {
+			meta[MetaKeySheet] = sheet
+		}
+		var lines []string
+		for i, col := range cols {
+			v := ""
+			if i < len(r.cells) {
+				v = strings.TrimSpace(r.cells[i])
+			}
+			if len(content) == 0 || content[i] {
+				if v == "" {
+					continue
+				}
+				// 只有一列内容时直接用原文，多列时带上列名
+				if len(content) == 1 {
+					lines = append(lines, v)
+				} else {
+					lines = append(lines, col.name+": "+v)
+				}
+				continue
+			}
+			if v != "" {
+				meta[col.name] = typedValue(v, col.kind)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		docs = append(docs, &schema.Document{Content: strings.Join(lines, "\n"), MetaData: meta})
+	}
+	return docs, nil
+}
+
+func (c *TableConfig) sheetDocs(sheet string, cols []column, data []tableRow) []*schema.Document {
+	names := make([]string, len(cols))
+	kinds := make(map[string]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+		kinds[col.name] = col.kind
+	}
+	size := c.RowsPerDoc
+	if size <= 0 || size > len(data) {
+		size = max(len(data), 1)
+	}
+
+	var docs []*schema.Document
+	for start := 0; ; start += size {
+		part := data[start:min(start+size, len(data))]
+		rows := [][]string{names}
+		for _, r := range part {
+			rows = append(rows, r.cells)
+		}
+		text := markdownTable(rows)
+		if sheet != "" {
+			text = "## " + sheet + "\n\n" + text
+		}
+		meta := map[string]any{
+			MetaKeyRows:        len(part),
+			MetaKeyColumns:     names,
+			MetaKeyColumnTypes: kinds,
+		}
+		if sheet != "" {
+			meta[MetaKeySheet] = sheet
+		}
+		if len(part) > 0 {
+			meta[MetaKeyRow] = part[0].num
+		}
+		docs = append(docs, &schema.Document{Content: text, MetaData: meta})
+		if start+size >= len(data) {
+			return docs
+		}
+	}
+}
+
+// trimTable 去掉全空的行和末尾全空的列
+func trimTable(rows []tableRow) []tableRow {
+	out := rows[:0]
+	width := 0
+	for _, r := range rows {
+		last := -1
+		for i, v := range r.cells {
+			if strings.TrimSpace(v) != "" {
+				last = i
+			}
+		}
+		if last < 0 {
+			continue
+		}
+		width = max(width, last+1)
+		out = append(out, r)
+	}
+	for i := range out {
+		if len(out[i].cells) > width {
+			out[i].cells = out[i].cells[:width]
+		}
+	}
+	return out
+}
+
+// detectHeader 第一行各列非空、不重复、不是数字或布尔值，并且满足其一：
+// 某列在后续行中是数字 / 布尔类型；或第一行明显比数据行短（如 FAQ 的“问题,答案”）
+func detectHeader(rows []tableRow) bool {
+	first := rows[0].cells
+	seen := map[string]bool{}
+	for _, v := range first {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] || valueKind(v) != "string" {
+			return false
+		}
+		seen[v] = true
+	}
+	data := rows[1:]
+	if len(data) == 0 {
+		return true
+	}
+	for i := range first {
+		if columnKind(data, i) != "string" {
+			return true
+		}
+	}
+	return avgLen([]tableRow{rows[0]})*2 <= avgLen(data)
+}
+
+func avgLen(rows []tableRow) float64 {
+	total, n := 0, 0
+	for _, r := range rows {
+		for _, v := range r.cells {
+			if v = strings.TrimSpace(v); v != "" {
+				total += utf8.RuneCountInString(v)
+				n++
+			}
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(total) / float64(n)
+}
+
+// columnKind 列中所有非空值都能解析为同一类型时取该类型，int 可以兼容为 float
+func columnKind(rows []tableRow, col int) string {
+	kind := ""
+	for _, r := range rows {
+		if col >= len(r.cells) {
+			continue
+		}
+		v := strings.TrimSpace(r.cells[col])
+		if v == "" {
+			continue
+		}
+		k := valueKind(v)
+		switch {
+		case kind == "" || kind == k:
+			kind = k
+		case kind == "int" && k == "float" || kind == "float" && k == "int":
+			kind = "float"
+		default:
+			return "string"
+		}
+	}
+	if kind == "" {
+		return "string"
+	}
+	return kind
+}
+
+// valueKind 单个值的类型；带前导 0 的数字（编号、邮编）视为字符串
+func valueKind(v string) string {
+	if len(v) > 1 && v[0] == '0' && v[1] != '.' {
+		return "string"
+	}
+	if _, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return "int"
+	}
+	// ParseFloat 也接受 NaN、Inf 与十六进制，这些按字符串处理
+	if _, err := strconv.ParseFloat(v, 64); err == nil && !strings.ContainsFunc(v, func(r rune) bool {
+		return unicode.IsLetter(r) && r != 'e' && r != 'E'
+	}) {
+		return "float"
+	}
+	if strings.EqualFold(v, "true") || strings.EqualFold(v, "false") {
+		return "bool"
+	}
+	return "string"
+}
+
+func typedValue(v, kind string) any {
+	switch kind {
+	case "int":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "bool":
+		return strings.EqualFold(v, "true")
+	}
+	return v
+}
+
+// columnLetter 0 → A，25 → Z，26 → AA
+func columnLetter(i int) string {
+	s := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		s = string(rune('A'+(i-1)%26)) + s
+	}
+	return s
+}
@@ -0,0 +1,327 @@
+package parsers
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// XLSXConfig XLSX 解析配置
+type XLSXConfig struct {
+	TableConfig
+	// Sheets 只解析这些工作表，为空表示全部；不存在的工作表会报错
+	Sheets []string
+	// MaxPartSize 单个 XML 部件解压后的大小上限，默认 64MB
+	MaxPartSize int64
+}
+
+// XLSXParser 纯 Go 解析 .xlsx：读取共享字符串与单元格值，按数字格式把日期序列号转为日期
+// 公式取缓存的计算结果；每个工作表单独处理表头与类型推断
+type XLSXParser struct {
+	cfg XLSXConfig
+}
+
+var _ parser.Parser = (*XLSXParser)(nil)
+
+// NewXLSXParser 创建 XLSX 解析器
+func NewXLSXParser(ctx context.Context, cfg *XLSXConfig) (*XLSXParser, error) {
+	if cfg == nil {
+		cfg = &XLSXConfig{}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	p := &XLSXParser{cfg: *cfg}
+	if p.cfg.MaxPartSize <= 0 {
+		p.cfg.MaxPartSize = defaultMaxPartSize
+	}
+	return p, nil
+}
+
+func (p *XLSXParser) Parse(ctx context.Context, reader io.Reader, opts ...parser.Option) ([]*schema.Document, error) {
+	option := parser.GetCommonOptions(&parser.Options{}, opts...)
+	zr, err := openZip(reader)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+	wb, err := p.readWorkbook(zr)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: %w", err)
+	}
+
+	sheets := wb.sheets
+	if len(p.cfg.Sheets) > 0 {
+		sheets = nil
+		for _, name := range p.cfg.Sheets {
+			i := wb.index(name)
+			if i < 0 {
+				return nil, fmt.Errorf("xlsx: sheet %q not found", name)
+			}
+			sheets = append(sheets, wb.sheets[i])
+		}
+	}
+
+	var tables []table
+	for _, s := range sheets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		root, err := p.read(zr, s.part)
+		if err != nil {
+			return nil, fmt.Errorf("xlsx: sheet %q: %w", s.name, err)
+		}
+		tables = append(tables, table{sheet: s.name, rows: wb.rows(root)})
+	}
+	return p.cfg.documents(tables, option.URI, option.ExtraMeta)
+}
+
+func (p *XLSXParser) read(zr *zip.Reader, name string) (*xnode, error) {
+	b, err := readPart(zr, name, p.cfg.MaxPartSize)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	return parseXML(b)
+}
+
+type xlsxSheet struct {
+	name string
+	part string
+}
+
+type xlsxWorkbook struct {
+	sheets  []xlsxSheet
+	strings []string
+	// dateStyles 单元格样式序号 → 是否为日期 / 时间格式
+	dateStyles []bool
+	date1904   bool
+}
+
+func (wb *xlsxWorkbook) index(name string) int {
+	for i, s := range wb.sheets {
+		if s.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *XLSXParser) readWorkbook(zr *zip.Reader) (*xlsxWorkbook, error) {
+	book, err := p.read(zr, "xl/workbook.xml")
+	if err != nil {
+		return nil, fmt.Errorf("workbook.xml: %w", err)
+	}
+	if book == nil {
+		return nil, errors.New("xl/workbook.xml not found")
+	}
+	rels, err := p.read(zr, "xl/_rels/workbook.xml.rels")
+	if err != nil {
+		return nil, fmt.Errorf("workbook.xml.rels: %w", err)
+	}
+	targets := map[string]string{}
+	for _, r := range rels.path("Relationships").children {
+		target := r.attr("Target")
+		// Target 一般相对 xl/，也可能是以 / 开头的包内绝对路径
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[r.attr("Id")] = target
+	}
+
+	wb := &xlsxWorkbook{}
+	pr := book.path("workbook", "workbookPr").attr("date1904")
+	wb.date1904 = pr == "1" || pr == "true"
+	for _, s := range book.path("workbook", "sheets").children {
+		if s.name != "sheet" {
+			continue
+		}
+		part, ok := targets[s.attr("id")]
+		if !ok {
+			return nil, fmt.Errorf("sheet %q has no relationship", s.attr("name"))
+		}
+		wb.sheets = append(wb.sheets, xlsxSheet{name: s.attr("name"), part: part})
+	}
+
+	shared, err := p.read(zr, "xl/sharedStrings.xml")
+	if err != nil {
+		return nil, fmt.Errorf("sharedStrings.xml: %w", err)
+	}
+	for _, si := range shared.path("sst").children {
+		if si.name == "si" {
+			wb.strings = append(wb.strings, richText(si))
+		}
+	}
+
+	styles, err := p.read(zr, "xl/styles.xml")
+	if err != nil {
+		return nil, fmt.Errorf("styles.xml: %w", err)
+	}
+	custom := map[int]string{}
+	for _, f := range styles.path("styleSheet", "numFmts").children {
+		if id, err := strconv.Atoi(f.attr("numFmtId")); err == nil {
+			custom[id] = f.attr("formatCode")
+		}
+	}
+	for _, xf := range styles.path("styleSheet", "cellXfs").children {
+		id, _ := strconv.Atoi(xf.attr("numFmtId"))
+		wb.dateStyles = append(wb.dateStyles, isDateFormat(id, custom[id]))
+	}
+	return wb, nil
+}
+
+// rows 读取工作表中的所有单元格，按单元格引用（如 C5）放到对应的行列
+func (wb *xlsxWorkbook) rows(sheet *xnode) []tableRow {
+	var rows []tableRow
+	for i, r := range sheet.path("worksheet", "sheetData").children {
+		if r.name != "row" {
+			continue
+		}
+		num, err := strconv.Atoi(r.attr("r"))
+		if err != nil {
+			num = i + 1
+		}
+		var cells []string
+		for _, c := range r.children {
+			if c.name != "c" {
+				continue
+			}
+			col := len(cells)
+			if ref := c.attr("r"); ref != "" {
+				col = columnIndex(ref)
+			}
+			if col < len(cells) {
+				continue
+			}
+			cells = append(cells, make([]string, col-len(cells))...)
+			cells = append(cells, wb.value(c))
+		}
+		rows = append(rows, tableRow{num: num, cells: cells})
+	}
+	return rows
+}
+
+// value 单元格的文本值；布尔值转为 true / false，日期格式的数字转为日期
+func (wb *xlsxWorkbook) value(c *xnode) string {
+	v := c.path("v").value()
+	switch c.attr("t") {
+	case "s":
+		if i, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && i >= 0 && i < len(wb.strings) {
+			return wb.strings[i]
+		}
+		return ""
+	case "inlineStr":
+		return richText(c.path("is"))
+	case "b":
+		return strconv.FormatBool(strings.TrimSpace(v) == "1")
+	case "str", "e", "d":
+		return v
+	}
+	if s, err := strconv.Atoi(c.attr("s")); err == nil && s >= 0 && s < len(wb.dateStyles) && wb.dateStyles[s] {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return excelTime(f, wb.date1904)
+		}
+	}
+	return v
+}
+
+// richText 共享字符串与内联字符串可能由多个带格式的片段组成，拼接全部 t，跳过注音 rPh
+func richText(n *xnode) string {
+	if n == nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, c := range n.children {
+		switch c.name {
+		case "t":
+			b.WriteString(c.text)
+		case "r":
+			b.WriteString(c.path("t").value())
+		}
+	}
+	return b.String()
+}
+
+// columnIndex 单元格引用中的列序号，C5 → 2
+func columnIndex(ref string) int {
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		n = n*26 + int(ch-'A'+1)
+	}
+	return n - 1
+}
+
+var dateFormatRe = regexp.MustCompile(`(?i)[ymdhs]`)
+
+// isDateFormat 内置格式 14-22、27-36、45-47、50-58 为日期 / 时间（后两段是中日韩日期格式）；
+// 自定义格式去掉引号文本、转义字符与 [颜色] 等方括号后含 y m d h s 即视为日期
+func isDateFormat(id int, code string) bool {
+	switch {
+	case id >= 14 && id <= 22, id >= 27 && id <= 36, id >= 45 && id <= 47, id >= 50 && id <= 58:
+		return true
+	case code == "":
+		return false
+	}
+	var b strings.Builder
+	quoted, bracket := false, false
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case ch == '"':
+			quoted = !quoted
+		case quoted:
+		case ch == '\\':
+			i++
+		case ch == '[':
+			bracket = true
+		case ch == ']':
+			bracket = false
+		case bracket:
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return dateFormatRe.MatchString(b.String())
+}
+
+// excelTime 日期序列号转文本：整数天输出日期，小于 1 输出时间，其余输出日期时间
+// 1900 日期系统沿用了 Lotus 1-2-3 的错误，把 1900 年当作闰年（序列号 60 为不存在的 1900-02-29），
+// 以 1899-12-30 为起点时 61 之前的日期要往后挪一天
+func excelTime(serial float64, date1904 bool) string {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	days, frac := math.Modf(serial)
+	secs := math.Round(frac * 86400)
+	// 四舍五入到整秒后可能正好是一整天
+	if secs == 86400 {
+		days, secs = days+1, 0
+	}
+	if !date1904 && days >= 1 && days < 61 {
+		days++
+	}
+	t := base.AddDate(0, 0, int(days)).Add(time.Duration(secs) * time.Second)
+	switch {
+	case secs == 0:
+		return t.Format(time.DateOnly)
+	case days == 0:
+		return t.Format(time.TimeOnly)
+	default:
+		return t.Format(time.DateTime)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/NuyoahCh/einotelos/einox/prompteval"
+	"github.com/NuyoahCh/einotelos/einox/prompts"
+	"github.com/cloudwego/eino-ext/components/model/deepseek"
+	"github.com/cloudwego/eino/components/model"
+)
+
+const promptEvalUsage = "usage: einox prompt-eval -data cases.jsonl -variants translator@1.0.0,translator@1.1.0[=model] [-prompts dir] [-judge rubric] [-assert contains:foo] [-out results.jsonl]"
+
+// runPromptEval einox prompt-eval 子命令：在数据集上对比多个模板版本 / 模型
+func runPromptEval(args []string) error {
+	fs := flag.NewFlagSet("prompt-eval", flag.ExitOnError)
+	dir := fs.String("prompts", "prompts", "提示词模板目录")
+	data := fs.String("data", "", "JSONL 数据集，每行 {\"id\", \"vars\", \"reference\", \"assert\"}")
+	variantsFlag := fs.String("variants", "", "参与对比的模板，逗号分隔，可用 =model 指定模型，如 translator@1.0.0,translator@1.1.0=deepseek-reasoner")
+	defaultModel := fs.String("model", "", "默认模型，为空时使用模板推荐的模型，再为空则用 deepseek-chat")
+	rubric := fs.String("judge", "", "评审的评分标准；以 @ 开头表示从文件读取，如 @rubric.txt；为空则只用断言打分")
+	judgeModel := fs.String("judge-model", "deepseek-chat", "评审使用的模型")
+	concurrency := fs.Int("c", 4, "并发请求数")
+	out := fs.String("out", "", "逐条结果写入 JSONL 文件")
+	var assertions []prompteval.Assertion
+	fs.Func("assert", "对所有样本生效的断言，可重复，如 contains:Eino、json、max_len:200", func(s string) error {
+		a, err := prompteval.ParseAssertion(s)
+		if err == nil {
+			assertions = append(assertions, a)
+		}
+		return err
+	})
+	fs.Func("price", "覆盖模型价格（美元/百万 token），可重复，如 deepseek-chat=0.27/1.10", func(s string) error {
+		name, value, ok := strings.Cut(s, "=")
+		in, outPrice, ok2 := strings.Cut(value, "/")
+		if !ok || !ok2 {
+			return errors.New("want model=input/output")
+		}
+		var p prompteval.Price
+		var err error
+		if p.Input, err = strconv.ParseFloat(in, 64); err != nil {
+			return err
+		}
+		if p.Output, err = strconv.ParseFloat(outPrice, 64); err != nil {
+			return err
+		}
+		prompteval.Prices[name] = p
+		return nil
+	})
+	_ = fs.Parse(args)
+	if *data == "" || *variantsFlag == "" {
+		return errors.New(promptEvalUsage)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cases, err := prompteval.LoadDataset(*data)
+	if err != nil {
+		return err
+	}
+	registry, err := prompts.NewRegistry(ctx, &prompts.Config{Dir: *dir})
+	if err != nil {
+		return err
+	}
+
+	models := make(map[string]model.BaseChatModel)
+	getModel := func(name string) (model.BaseChatModel, error) {
+		if m, ok := models[name]; ok {
+			return m, nil
+		}
+		m, err := deepseek.NewChatModel(ctx, &deepseek.ChatModelConfig{
+			APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
+			Model:   name,
+			BaseURL: "https://api.deepseek.com",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create model %s: %w", name, err)
+		}
+		models[name] = m
+		return m, nil
+	}
+
+	cfg := &prompteval.Config{Assertions: assertions, Concurrency: *concurrency}
+	for _, spec := range strings.Split(*variantsFlag, ",") {
+		ref, modelName, _ := strings.Cut(strings.TrimSpace(spec), "=")
+		t, err := registry.Get(ref)
+		if err != nil {
+			return err
+		}
+		if modelName == "" {
+			modelName = *defaultModel
+		}
+		if modelName == "" {
+			modelName = t.Model
+		}
+		if modelName == "" {
+			modelName = "deepseek-chat"
+		}
+		m, err := getModel(modelName)
+		if err != nil {
+			return err
+		}
+		cfg.Variants = append(cfg.Variants, &prompteval.Variant{
+			Name:     t.Ref() + " / " + modelName,
+			Template: t.ChatTemplate(),
+			Model:    m,
+			Price:    prompteval.Prices[modelName],
+		})
+	}
+
+	if *rubric != "" {
+		text := *rubric
+		if path, ok := strings.CutPrefix(text, "@"); ok {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			text = string(b)
+		}
+		m, err := getModel(*judgeModel)
+		if err != nil {
+			return err
+		}
+		cfg.Judge = &prompteval.Judge{Model: m, Rubric: text}
+	}
+	if cfg.Judge == nil && len(cfg.Assertions) == 0 {
+		hasAssert := false
+		for _, c := range cases {
+			hasAssert = hasAssert || len(c.Assertions) > 0
+		}
+		if !hasAssert {
+			return errors.New("nothing to score: set -judge or -assert, or add \"assert\" to the dataset")
+		}
+	}
+
+	fmt.Printf("数据集 %d 条，对比 %d 个版本...\n\n", len(cases), len(cfg.Variants))
+	report, err := prompteval.Run(ctx, cfg, cases)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		return err
+	}
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		if err := report.WriteJSONL(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("\n逐条结果已写入: %s\n", *out)
+	}
+	return nil
+}
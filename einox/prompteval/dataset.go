@@ -0,0 +1,148 @@
+package prompteval
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Case 数据集中的一条样本，对应 JSONL 的一行：
+//
+//	{"id": "t1", "vars": {"text": "..."}, "reference": "参考答案，可选", "assert": ["contains:Eino", "max_len:80"]}
+type Case struct {
+	ID   string         `json:"id"`
+	Vars map[string]any `json:"vars"`
+	// Reference 参考答案，提供给评审模型参考
+	Reference  string      `json:"reference,omitempty"`
+	Assertions []Assertion `json:"assert,omitempty"`
+}
+
+// LoadDataset 读取 JSONL 数据集，空行与 # 开头的行忽略；没有 id 的样本按行号编号
+func LoadDataset(path string) ([]*Case, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cases []*Case
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 8*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if len(c.Vars) == 0 {
+			return nil, fmt.Errorf("%s:%d: missing vars", path, line)
+		}
+		if c.ID == "" {
+			c.ID = strconv.Itoa(line)
+		}
+		cases = append(cases, &c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("%s: empty dataset", path)
+	}
+	return cases, nil
+}
+
+// Assertion 对输出的断言，写成 type:value，如 contains:Eino、not_contains:抱歉、regex:^\d+$、json、max_len:100
+type Assertion struct {
+	Type  string
+	Value string
+}
+
+// ParseAssertion 解析 type:value 形式的断言
+func ParseAssertion(s string) (Assertion, error) {
+	typ, value, _ := strings.Cut(strings.TrimSpace(s), ":")
+	a := Assertion{Type: strings.ToLower(typ), Value: value}
+	switch a.Type {
+	case "contains", "not_contains", "icontains":
+		if value == "" {
+			return a, fmt.Errorf("assertion %q: missing value", s)
+		}
+	case "regex":
+		if _, err := regexp.Compile(value); err != nil {
+			return a, fmt.Errorf("assertion %q: %w", s, err)
+		}
+	case "json":
+	case "max_len":
+		if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+			return a, fmt.Errorf("assertion %q: want positive length", s)
+		}
+	default:
+		return a, fmt.Errorf("unknown assertion type %q", a.Type)
+	}
+	return a, nil
+}
+
+func (a Assertion) String() string {
+	if a.Value == "" {
+		return a.Type
+	}
+	return a.Type + ":" + a.Value
+}
+
+func (a *Assertion) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.New("assertion must be a string like \"contains:foo\"")
+	}
+	parsed, err := ParseAssertion(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func (a Assertion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// Check 检查输出是否满足断言
+func (a Assertion) Check(output string) bool {
+	switch a.Type {
+	case "contains":
+		return strings.Contains(output, a.Value)
+	case "not_contains":
+		return !strings.Contains(output, a.Value)
+	case "icontains":
+		return strings.Contains(strings.ToLower(output), strings.ToLower(a.Value))
+	case "regex":
+		return regexp.MustCompile(a.Value).MatchString(output)
+	case "json":
+		return json.Valid([]byte(stripFence(output)))
+	case "max_len":
+		n, _ := strconv.Atoi(a.Value)
+		return utf8.RuneCountInString(strings.TrimSpace(output)) <= n
+	}
+	return false
+}
+
+// stripFence 去掉 ```json ... ``` 代码块外壳
+func stripFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	s = strings.TrimPrefix(s, "```")
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "```"))
+}
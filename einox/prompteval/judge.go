@@ -0,0 +1,56 @@
+package prompteval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Judge 用评分标准让模型给输出打分
+type Judge struct {
+	Model model.BaseChatModel
+	// Rubric 评分标准，如 "译文是否准确、流畅，术语是否保留英文原文"
+	Rubric string
+}
+
+// Score 给一次输出打 1~10 分，返回归一化到 0~1 的分数和理由
+func (j *Judge) Score(ctx context.Context, input []*schema.Message, output, reference string) (float64, string, error) {
+	var b strings.Builder
+	b.WriteString("对话输入：\n")
+	for _, m := range input {
+		fmt.Fprintf(&b, "[%s] %s\n", m.Role, m.Content)
+	}
+	fmt.Fprintf(&b, "\n待评估的输出：\n%s\n", output)
+	if reference != "" {
+		fmt.Fprintf(&b, "\n参考答案：\n%s\n", reference)
+	}
+
+	resp, err := j.Model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage("你是严格的评审，按下面的评分标准给模型输出打 1~10 分（整数）。\n评分标准：\n" + j.Rubric +
+			"\n只输出 JSON，格式：{\"score\": 7, \"reason\": \"一句话理由\"}"),
+		schema.UserMessage(b.String()),
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("judge: %w", err)
+	}
+	content := resp.Content
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return 0, "", fmt.Errorf("judge returned no json: %q", content)
+	}
+	var v struct {
+		Score  float64 `json:"score"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &v); err != nil {
+		return 0, "", fmt.Errorf("parse judge output: %w", err)
+	}
+	if v.Score < 1 || v.Score > 10 {
+		return 0, "", fmt.Errorf("judge score out of range: %v", v.Score)
+	}
+	return v.Score / 10, v.Reason, nil
+}
@@ -0,0 +1,205 @@
+package prompteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Summary 一个 Variant 的汇总
+type Summary struct {
+	Variant string `json:"variant"`
+	Cases   int    `json:"cases"`
+	Errors  int    `json:"errors"`
+	Wins    int    `json:"wins"`
+	Ties    int    `json:"ties"`
+	Losses  int    `json:"losses"`
+	// Excluded 因评审失败未参与胜负比较的样本数
+	Excluded int `json:"excluded"`
+	// WinRate (胜 + 0.5 × 平) / 参与比较的样本数
+	WinRate  float64 `json:"win_rate"`
+	AvgScore float64 `json:"avg_score"`
+	// AssertPassRate 断言通过率，没有断言时为 -1
+	AssertPassRate float64 `json:"assert_pass_rate"`
+	// AvgJudge 评审平均分（0~1），没有评审分数时为 -1
+	AvgJudge   float64       `json:"avg_judge"`
+	AvgLatency time.Duration `json:"avg_latency_ns"`
+	P95Latency time.Duration `json:"p95_latency_ns"`
+	Tokens     int           `json:"tokens"`
+	Cost       float64       `json:"cost"`
+}
+
+// Report 评测报告
+type Report struct {
+	Results   []*Result  `json:"results"`
+	Summaries []*Summary `json:"summaries"`
+}
+
+// newReport 逐样本比较各 Variant 的得分：唯一最高分记胜，并列最高记平，其余记负；出错的记负
+// 任一 Variant 评审失败时该样本不参与比较
+func newReport(cfg *Config, cases []*Case, results []*Result) *Report {
+	n := len(cfg.Variants)
+	summaries := make([]*Summary, n)
+	for i, v := range cfg.Variants {
+		summaries[i] = &Summary{Variant: v.Name, Cases: len(cases)}
+	}
+
+	for ci := range cases {
+		row := results[ci*n : (ci+1)*n]
+		if slices.ContainsFunc(row, func(r *Result) bool { return r.JudgeFailed }) {
+			for _, s := range summaries {
+				s.Excluded++
+			}
+			continue
+		}
+		best, top := -1.0, 0
+		for _, r := range row {
+			if r.Err != "" {
+				continue
+			}
+			if r.Score > best {
+				best, top = r.Score, 1
+			} else if r.Score == best {
+				top++
+			}
+		}
+		for vi, r := range row {
+			s := summaries[vi]
+			switch {
+			case r.Err != "":
+				s.Losses++
+			case r.Score == best && top == 1:
+				s.Wins++
+			case r.Score == best:
+				s.Ties++
+			default:
+				s.Losses++
+			}
+		}
+	}
+
+	for vi, s := range summaries {
+		var (
+			latencies          []time.Duration
+			scoreSum, judgeSum float64
+			judged, ok         int
+			asserts, passed    int
+		)
+		for ci := range cases {
+			r := results[ci*n+vi]
+			s.Tokens += r.PromptTokens + r.CompletionTokens
+			s.Cost += r.Cost
+			if r.Err != "" {
+				s.Errors++
+				continue
+			}
+			ok++
+			scoreSum += r.Score
+			latencies = append(latencies, r.Latency)
+			if r.JudgeScore >= 0 {
+				judged++
+				judgeSum += r.JudgeScore
+			}
+			for _, a := range r.Assertions {
+				asserts++
+				if a.Pass {
+					passed++
+				}
+			}
+		}
+		if compared := s.Cases - s.Excluded; compared > 0 {
+			s.WinRate = (float64(s.Wins) + 0.5*float64(s.Ties)) / float64(compared)
+		}
+		s.AssertPassRate, s.AvgJudge = -1, -1
+		if ok > 0 {
+			s.AvgScore = scoreSum / float64(ok)
+		}
+		if asserts > 0 {
+			s.AssertPassRate = float64(passed) / float64(asserts)
+		}
+		if judged > 0 {
+			s.AvgJudge = judgeSum / float64(judged)
+		}
+		if len(latencies) > 0 {
+			sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+			var total time.Duration
+			for _, l := range latencies {
+				total += l
+			}
+			s.AvgLatency = total / time.Duration(len(latencies))
+			s.P95Latency = latencies[(len(latencies)*95+99)/100-1]
+		}
+	}
+	return &Report{Results: results, Summaries: summaries}
+}
+
+// WriteText 输出对比表格
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIANT\tWIN RATE\tW/T/L\tSCORE\tASSERT\tJUDGE\tAVG LATENCY\tP95\tTOKENS\tCOST($)\tERRORS")
+	for _, s := range r.Summaries {
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%d/%d/%d\t%.3f\t%s\t%s\t%s\t%s\t%d\t%.4f\t%d\n",
+			s.Variant, s.WinRate*100, s.Wins, s.Ties, s.Losses, s.AvgScore,
+			percentOrDash(s.AssertPassRate), scoreOrDash(s.AvgJudge),
+			s.AvgLatency.Round(time.Millisecond), s.P95Latency.Round(time.Millisecond),
+			s.Tokens, s.Cost, s.Errors)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if len(r.Summaries) > 0 && r.Summaries[0].Excluded > 0 {
+		if _, err := fmt.Fprintf(w, "评审失败、未参与胜负比较的样本：%d\n", r.Summaries[0].Excluded); err != nil {
+			return err
+		}
+	}
+
+	var failed []string
+	for _, res := range r.Results {
+		switch {
+		case res.Err != "":
+			failed = append(failed, fmt.Sprintf("  [%s] %s: %s", res.CaseID, res.Variant, res.Err))
+		default:
+			for _, a := range res.Assertions {
+				if !a.Pass {
+					failed = append(failed, fmt.Sprintf("  [%s] %s: 断言失败 %s", res.CaseID, res.Variant, a.Assertion))
+				}
+			}
+		}
+	}
+	if len(failed) > 0 {
+		_, err := fmt.Fprintf(w, "\n失败明细：\n%s\n", strings.Join(failed, "\n"))
+		return err
+	}
+	return nil
+}
+
+// WriteJSONL 每行一条结果，便于后续分析
+func (r *Report) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, res := range r.Results {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func percentOrDash(v float64) string {
+	if v < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", v*100)
+}
+
+func scoreOrDash(v float64) string {
+	if v < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", v*10)
+}
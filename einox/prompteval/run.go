@@ -0,0 +1,163 @@
+package prompteval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+)
+
+// Price 每百万 token 的价格
+type Price struct {
+	Input  float64
+	Output float64
+}
+
+// Prices 常用模型的参考价格（美元 / 百万 token），以服务商当前报价为准，可用命令行参数覆盖
+var Prices = map[string]Price{
+	"deepseek-chat":     {Input: 0.27, Output: 1.10},
+	"deepseek-reasoner": {Input: 0.55, Output: 2.19},
+}
+
+// Variant 参与对比的一组配置：模板版本 + 模型
+type Variant struct {
+	// Name 报告中显示的名称，如 translator@1.1.0 / deepseek-chat
+	Name     string
+	Template prompt.ChatTemplate
+	Model    model.BaseChatModel
+	Price    Price
+}
+
+// Config 评测配置
+type Config struct {
+	Variants []*Variant
+	// Judge 为空时只用断言打分
+	Judge *Judge
+	// Assertions 对所有样本生效的断言，与样本自带的断言合并
+	Assertions []Assertion
+	// Concurrency 并发请求数，默认 4
+	Concurrency int
+}
+
+// AssertionResult 一条断言的检查结果
+type AssertionResult struct {
+	Assertion Assertion `json:"assertion"`
+	Pass      bool      `json:"pass"`
+}
+
+// Result 一个样本在一个 Variant 上的结果
+type Result struct {
+	CaseID           string            `json:"case_id"`
+	Variant          string            `json:"variant"`
+	Output           string            `json:"output"`
+	Err              string            `json:"error,omitempty"`
+	Latency          time.Duration     `json:"latency_ns"`
+	PromptTokens     int               `json:"prompt_tokens"`
+	CompletionTokens int               `json:"completion_tokens"`
+	Cost             float64           `json:"cost"`
+	Assertions       []AssertionResult `json:"assertions,omitempty"`
+	// JudgeScore 评审分数（0~1），未配置评审或评审失败时为 -1
+	JudgeScore  float64 `json:"judge_score"`
+	JudgeReason string  `json:"judge_reason,omitempty"`
+	// JudgeFailed 评审调用失败（原因见 Err）；该样本不参与胜负比较
+	JudgeFailed bool `json:"judge_failed,omitempty"`
+	// Score 综合得分（0~1）：断言通过率与评审分数的平均值
+	Score float64 `json:"score"`
+}
+
+// Run 在数据集上运行所有 Variant，返回逐条结果与汇总
+// 单次调用失败记录在 Result.Err 中，不中断评测
+func Run(ctx context.Context, cfg *Config, cases []*Case) (*Report, error) {
+	if cfg == nil || len(cfg.Variants) < 2 {
+		return nil, errors.New("at least two variants are required")
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("empty dataset")
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	results := make([]*Result, len(cases)*len(cfg.Variants))
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, concurrency)
+	)
+	for ci, c := range cases {
+		for vi, v := range cfg.Variants {
+			wg.Add(1)
+			go func(idx int, c *Case, v *Variant) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					results[idx] = &Result{CaseID: c.ID, Variant: v.Name, Err: ctx.Err().Error(), JudgeScore: -1}
+					return
+				}
+				results[idx] = runOne(ctx, cfg, c, v)
+			}(ci*len(cfg.Variants)+vi, c, v)
+		}
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return newReport(cfg, cases, results), nil
+}
+
+func runOne(ctx context.Context, cfg *Config, c *Case, v *Variant) *Result {
+	r := &Result{CaseID: c.ID, Variant: v.Name, JudgeScore: -1}
+	messages, err := v.Template.Format(ctx, c.Vars)
+	if err != nil {
+		r.Err = fmt.Sprintf("format: %v", err)
+		return r
+	}
+
+	start := time.Now()
+	resp, err := v.Model.Generate(ctx, messages)
+	r.Latency = time.Since(start)
+	if err != nil {
+		r.Err = fmt.Sprintf("generate: %v", err)
+		return r
+	}
+	r.Output = resp.Content
+	if resp.ResponseMeta != nil && resp.ResponseMeta.Usage != nil {
+		r.PromptTokens = resp.ResponseMeta.Usage.PromptTokens
+		r.CompletionTokens = resp.ResponseMeta.Usage.CompletionTokens
+	}
+	r.Cost = (float64(r.PromptTokens)*v.Price.Input + float64(r.CompletionTokens)*v.Price.Output) / 1e6
+
+	var parts []float64
+	assertions := append(append([]Assertion(nil), cfg.Assertions...), c.Assertions...)
+	if len(assertions) > 0 {
+		passed := 0
+		for _, a := range assertions {
+			ok := a.Check(r.Output)
+			if ok {
+				passed++
+			}
+			r.Assertions = append(r.Assertions, AssertionResult{Assertion: a, Pass: ok})
+		}
+		parts = append(parts, float64(passed)/float64(len(assertions)))
+	}
+	if cfg.Judge != nil {
+		score, reason, err := cfg.Judge.Score(ctx, messages, r.Output, c.Reference)
+		if err != nil {
+			// 缺了评审分数的得分与其他 Variant 不可比，按出错处理
+			r.Err, r.JudgeFailed = err.Error(), true
+			return r
+		}
+		r.JudgeScore, r.JudgeReason = score, reason
+		parts = append(parts, score)
+	}
+	for _, p := range parts {
+		r.Score += p / float64(len(parts))
+	}
+	return r
+}
@@ -0,0 +1,75 @@
+package prompts
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Bind 把结构体转成模板变量：字段名默认转成 snake_case（VIPLevel -> vip_level），
+// 可用 `prompt:"name"` 指定、`prompt:"-"` 跳过；字段值保持原类型，模板中可直接判断、遍历
+// 传入多个结构体时合并为一份变量，同名时后者覆盖前者
+func Bind(values ...any) (map[string]any, error) {
+	vars := make(map[string]any)
+	for _, value := range values {
+		v := reflect.ValueOf(value)
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, fmt.Errorf("bind: nil %T", value)
+			}
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+			for _, k := range v.MapKeys() {
+				vars[k.String()] = v.MapIndex(k).Interface()
+			}
+			continue
+		}
+		if v.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("bind: want struct or map[string]T, got %T", value)
+		}
+		bindStruct(v, vars)
+	}
+	return vars, nil
+}
+
+func bindStruct(v reflect.Value, vars map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Tag.Get("prompt")
+		if name == "-" {
+			continue
+		}
+		// 匿名嵌入的结构体字段提升到同一层
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			bindStruct(v.Field(i), vars)
+			continue
+		}
+		if name == "" {
+			name = snakeCase(f.Name)
+		}
+		vars[name] = v.Field(i).Interface()
+	}
+}
+
+// snakeCase 驼峰转 snake_case，连续大写视为缩写：UserID -> user_id，VIPLevel -> vip_level
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,190 @@
+package prompts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Example 一条 few-shot 示例：输入与理想输出
+type Example struct {
+	Input  string `yaml:"input"`
+	Output string `yaml:"output"`
+}
+
+// SelectorConfig 示例选择器配置
+type SelectorConfig struct {
+	Embedder embedding.Embedder
+	// K 每次最多选几条示例，默认 3
+	K int
+	// MinScore 余弦相似度低于该值的示例不选，默认 0 表示不限制
+	MinScore float64
+}
+
+// ExampleSelector 按向量相似度选择与当前输入最接近的示例
+type ExampleSelector struct {
+	embedder embedding.Embedder
+	k        int
+	minScore float64
+
+	mu       sync.RWMutex
+	examples []Example
+	vectors  [][]float64
+}
+
+// NewExampleSelector 创建选择器并向量化初始示例
+func NewExampleSelector(ctx context.Context, cfg *SelectorConfig, examples ...Example) (*ExampleSelector, error) {
+	if cfg == nil || cfg.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	s := &ExampleSelector{embedder: cfg.Embedder, k: cfg.K, minScore: cfg.MinScore}
+	if s.k <= 0 {
+		s.k = 3
+	}
+	if err := s.Add(ctx, examples...); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Add 向量化并加入示例
+func (s *ExampleSelector) Add(ctx context.Context, examples ...Example) error {
+	if len(examples) == 0 {
+		return nil
+	}
+	inputs := make([]string, len(examples))
+	for i, e := range examples {
+		inputs[i] = e.Input
+	}
+	vectors, err := s.embedder.EmbedStrings(ctx, inputs)
+	if err != nil {
+		return fmt.Errorf("embed examples: %w", err)
+	}
+	if len(vectors) != len(examples) {
+		return fmt.Errorf("embed examples: got %d vectors for %d examples", len(vectors), len(examples))
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.examples = append(s.examples, examples...)
+	s.vectors = append(s.vectors, vectors...)
+	return nil
+}
+
+// Len 示例数量
+func (s *ExampleSelector) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.examples)
+}
+
+// Select 返回与 input 最相似的至多 K 条示例，按相似度升序排列（最相似的一条最靠近真正的问题）
+func (s *ExampleSelector) Select(ctx context.Context, input string) ([]Example, error) {
+	if s.Len() == 0 {
+		return nil, nil
+	}
+	vectors, err := s.embedder.EmbedStrings(ctx, []string{input})
+	if err != nil {
+		return nil, fmt.Errorf("embed input: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embed input: got %d vectors", len(vectors))
+	}
+
+	type scored struct {
+		example Example
+		score   float64
+	}
+	s.mu.RLock()
+	candidates := make([]scored, 0, len(s.examples))
+	for i, v := range s.vectors {
+		if score := cosine(vectors[0], v); score >= s.minScore {
+			candidates = append(candidates, scored{s.examples[i], score})
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > s.k {
+		candidates = candidates[:s.k]
+	}
+	out := make([]Example, len(candidates))
+	for i, c := range candidates {
+		out[len(out)-1-i] = c.example
+	}
+	return out, nil
+}
+
+// Messages 把选出的示例转成 user / assistant 消息对，可直接作为 MessagesPlaceholder 的值
+func (s *ExampleSelector) Messages(ctx context.Context, input string) ([]*schema.Message, error) {
+	examples, err := s.Select(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*schema.Message, 0, len(examples)*2)
+	for _, e := range examples {
+		msgs = append(msgs, schema.UserMessage(e.Input), schema.AssistantMessage(e.Output, nil))
+	}
+	return msgs, nil
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// WithExamples 包装 ChatTemplate：Format 时取变量 inputKey 的值选择示例，写入变量 placeholderKey，
+// 模板中对应位置放一个 Placeholder(placeholderKey, true)（或 schema.MessagesPlaceholder）
+func WithExamples(inner prompt.ChatTemplate, selector *ExampleSelector, inputKey, placeholderKey string) prompt.ChatTemplate {
+	return &fewShotTemplate{inner: inner, selector: selector, inputKey: inputKey, placeholderKey: placeholderKey}
+}
+
+type fewShotTemplate struct {
+	inner          prompt.ChatTemplate
+	selector       *ExampleSelector
+	inputKey       string
+	placeholderKey string
+}
+
+func (f *fewShotTemplate) Format(ctx context.Context, vs map[string]any, opts ...prompt.Option) ([]*schema.Message, error) {
+	input, ok := vs[f.inputKey]
+	if !ok {
+		return nil, fmt.Errorf("few-shot input variable %q not found", f.inputKey)
+	}
+	msgs, err := f.selector.Messages(ctx, fmt.Sprint(input))
+	if err != nil {
+		return nil, err
+	}
+	// 复制一份变量，不修改调用方的 map
+	merged := make(map[string]any, len(vs)+1)
+	for k, v := range vs {
+		merged[k] = v
+	}
+	merged[f.placeholderKey] = msgs
+	return f.inner.Format(ctx, merged, opts...)
+}
+
+func (f *fewShotTemplate) GetType() string {
+	return "FewShot"
+}
+
+func (f *fewShotTemplate) IsCallbacksEnabled() bool {
+	return true // 回调由内层模板触发
+}
@@ -0,0 +1,95 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Funcs GoTemplate 模板可用的辅助函数，参数顺序便于管道调用，如 {{.interests | join "、"}}
+// Jinja2 模板直接使用内置过滤器（join、truncate、default 等），无需额外注册
+var Funcs = template.FuncMap{
+	"join":     join,
+	"truncate": truncate,
+	"default":  defaultValue,
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"trim":     strings.TrimSpace,
+	"add":      func(a, b int) int { return a + b },
+}
+
+// join 用 sep 连接任意切片的元素
+func join(sep string, list any) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+	items := make([]string, v.Len())
+	for i := range items {
+		items[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(items, sep)
+}
+
+// truncate 截断到 n 个字符，超出部分用 … 代替
+func truncate(n int, s string) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n]) + "…"
+}
+
+// defaultValue v 为零值时返回 def
+func defaultValue(def, v any) any {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return def
+	}
+	return v
+}
+
+// NewMessage 创建一条消息模板：按 GoTemplate 渲染时可以使用 Funcs 中的辅助函数，
+// 其他格式与 schema.Message 的行为一致
+func NewMessage(role schema.RoleType, content string) schema.MessagesTemplate {
+	return &funcMessage{role: role, content: content}
+}
+
+// Placeholder 与 schema.MessagesPlaceholder 相同，但 key 与是否可选对 Check 可见；
+// 需要静态校验的模板请用它代替 schema.MessagesPlaceholder
+func Placeholder(key string, optional bool) schema.MessagesTemplate {
+	return &placeholderMessage{key: key, optional: optional, inner: schema.MessagesPlaceholder(key, optional)}
+}
+
+type placeholderMessage struct {
+	key      string
+	optional bool
+	inner    schema.MessagesTemplate
+}
+
+func (m *placeholderMessage) Format(ctx context.Context, vs map[string]any, formatType schema.FormatType) ([]*schema.Message, error) {
+	return m.inner.Format(ctx, vs, formatType)
+}
+
+type funcMessage struct {
+	role    schema.RoleType
+	content string
+}
+
+func (m *funcMessage) Format(ctx context.Context, vs map[string]any, formatType schema.FormatType) ([]*schema.Message, error) {
+	if formatType != schema.GoTemplate {
+		return (&schema.Message{Role: m.role, Content: m.content}).Format(ctx, vs, formatType)
+	}
+	tpl, err := template.New("prompt").Funcs(Funcs).Option("missingkey=error").Parse(m.content)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	if err := tpl.Execute(&b, vs); err != nil {
+		return nil, err
+	}
+	return []*schema.Message{{Role: m.role, Content: b.String()}}, nil
+}
@@ -0,0 +1,104 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/NuyoahCh/einotelos/einox/media"
+	"github.com/cloudwego/eino/schema"
+)
+
+// MediaMessage 创建一条多模态用户消息模板：content 渲染为文本片段，mediaKeys 对应的变量依次展开为图片 / 文件片段
+// 变量可以是 []byte、schema.MessageInputPart 或其切片、http(s) 图片地址（string / []string）；
+// 变量值可能来自用户输入，因此不读取本地路径，需要时使用 MediaMessageIn。
+// 变量不存在时跳过，需要必填时在 Variables 中声明
+func MediaMessage(content string, mediaKeys ...string) schema.MessagesTemplate {
+	return MediaMessageIn(nil, content, mediaKeys...)
+}
+
+// MediaMessageIn 与 MediaMessage 相同，另外允许变量是本地路径，由 loader 读取；
+// loader 必须设置 BaseDir，路径不在 BaseDir 之下时报错
+func MediaMessageIn(loader *media.Loader, content string, mediaKeys ...string) schema.MessagesTemplate {
+	return &mediaMessage{text: &funcMessage{role: schema.User, content: content}, keys: mediaKeys, loader: loader}
+}
+
+type mediaMessage struct {
+	text   *funcMessage
+	keys   []string
+	loader *media.Loader
+}
+
+func (m *mediaMessage) Format(ctx context.Context, vs map[string]any, formatType schema.FormatType) ([]*schema.Message, error) {
+	var parts []schema.MessageInputPart
+	if m.text.content != "" {
+		msgs, err := m.text.Format(ctx, vs, formatType)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, media.Text(msgs[0].Content))
+	}
+	for _, key := range m.keys {
+		value, ok := vs[key]
+		if !ok || value == nil {
+			continue
+		}
+		p, err := m.parts(value)
+		if err != nil {
+			return nil, fmt.Errorf("media %s: %w", key, err)
+		}
+		parts = append(parts, p...)
+	}
+	return []*schema.Message{{Role: schema.User, UserInputMultiContent: parts}}, nil
+}
+
+// parts 把变量值转成消息片段
+func (m *mediaMessage) parts(value any) ([]schema.MessageInputPart, error) {
+	switch v := value.(type) {
+	case schema.MessageInputPart:
+		return []schema.MessageInputPart{v}, nil
+	case *schema.MessageInputPart:
+		return []schema.MessageInputPart{*v}, nil
+	case []schema.MessageInputPart:
+		return v, nil
+	case string:
+		if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+			return []schema.MessageInputPart{media.ImageURL(v)}, nil
+		}
+		if m.loader == nil || m.loader.BaseDir == "" {
+			return nil, fmt.Errorf("local path %q is not allowed without a loader base dir", v)
+		}
+		p, err := m.loader.Path(v)
+		if err != nil {
+			return nil, err
+		}
+		return []schema.MessageInputPart{p}, nil
+	case []string:
+		var out []schema.MessageInputPart
+		for _, s := range v {
+			p, err := m.parts(s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, p...)
+		}
+		return out, nil
+	case []byte:
+		if media.ImageTypes[media.DetectMIME(v, "")] {
+			p, err := media.ImageBytes(v, "")
+			return []schema.MessageInputPart{p}, err
+		}
+		p, err := media.FileBytes(v, "")
+		return []schema.MessageInputPart{p}, err
+	}
+	return nil, fmt.Errorf("unsupported media value %T", value)
+}
+
+// isMedia checkType 中 TypeMedia 的判断
+func isMedia(value any) bool {
+	switch value.(type) {
+	case schema.MessageInputPart, *schema.MessageInputPart, []schema.MessageInputPart, string, []string, []byte:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+// Package promptstest 在测试中校验提示词模板的变量
+package promptstest
+
+import (
+	"testing"
+
+	"github.com/NuyoahCh/einotelos/einox/prompts"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Check 校验模板变量，有问题时标记测试失败并列出全部问题
+//
+//	promptstest.Check(t, schema.FString, msgs, nil, map[string]any{"role": "...", "question": "..."})
+func Check(tb testing.TB, format schema.FormatType, templates []schema.MessagesTemplate, vars []prompts.Variable, values map[string]any) {
+	tb.Helper()
+	report, err := prompts.Check(format, templates, vars, values)
+	if err != nil {
+		tb.Fatalf("parse prompt: %v", err)
+	}
+	if !report.OK() {
+		tb.Errorf("prompt variables: %s", report)
+	}
+}
+
+// CheckTemplate 校验注册表中的模板，values 为 nil 时只做静态检查
+func CheckTemplate(tb testing.TB, t *prompts.Template, values map[string]any) {
+	tb.Helper()
+	report, err := t.Check(values)
+	if err != nil {
+		tb.Fatalf("prompt %s: %v", t.Ref(), err)
+	}
+	if !report.OK() {
+		tb.Errorf("prompt %s: %s", t.Ref(), report)
+	}
+}
+
+// CheckDir 加载目录下的全部模板并逐个做静态检查
+func CheckDir(tb testing.TB, dir string) {
+	tb.Helper()
+	registry, err := prompts.NewRegistry(tb.Context(), &prompts.Config{Dir: dir})
+	if err != nil {
+		tb.Fatalf("load prompts: %v", err)
+	}
+	for _, t := range registry.List() {
+		CheckTemplate(tb, t, nil)
+	}
+}
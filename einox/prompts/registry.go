@@ -0,0 +1,231 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config 注册表配置
+type Config struct {
+	// Dir 模板目录，递归加载其中的 .yaml / .yml 文件
+	Dir string
+	// Pins 按名称固定版本，如 {"translator": "1.0.0"}；未固定的名称取最新版本
+	Pins map[string]string
+	// ReloadInterval 热加载的轮询间隔，0 表示不热加载
+	ReloadInterval time.Duration
+}
+
+// Registry 提示词模板注册表：同名模板可以有多个版本，按 name 或 name@version 取用
+type Registry struct {
+	dir  string
+	pins map[string]string
+
+	mu        sync.RWMutex
+	templates map[string][]*Template // name -> 按版本升序
+	modTimes  map[string]time.Time   // 文件 -> 修改时间，用于判断是否需要重新加载
+}
+
+// NewRegistry 加载目录下的全部模板；ReloadInterval > 0 时在后台轮询目录变化，ctx 结束后停止
+func NewRegistry(ctx context.Context, cfg *Config) (*Registry, error) {
+	if cfg == nil || cfg.Dir == "" {
+		return nil, fmt.Errorf("prompt dir is required")
+	}
+	r := &Registry{dir: cfg.Dir, pins: make(map[string]string)}
+	for name, version := range cfg.Pins {
+		r.pins[name] = version
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	for name, version := range r.pins {
+		if _, err := r.Get(name + "@" + version); err != nil {
+			return nil, fmt.Errorf("pin %s@%s: %w", name, version, err)
+		}
+	}
+	if cfg.ReloadInterval > 0 {
+		go r.watch(ctx, cfg.ReloadInterval)
+	}
+	return r, nil
+}
+
+// Reload 重新扫描目录并整体替换模板；任一文件有错时返回错误，保留原有模板不变
+func (r *Registry) Reload() error {
+	templates := make(map[string][]*Template)
+	modTimes := make(map[string]time.Time)
+	err := filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		t, err := LoadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, other := range templates[t.Name] {
+			if compareVersion(other.Version, t.Version) == 0 {
+				return fmt.Errorf("duplicate prompt %s in %s and %s", t.Ref(), other.Path, path)
+			}
+		}
+		templates[t.Name] = append(templates[t.Name], t)
+		modTimes[path] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, versions := range templates {
+		sort.Slice(versions, func(i, j int) bool {
+			return compareVersion(versions[i].Version, versions[j].Version) < 0
+		})
+	}
+
+	r.mu.Lock()
+	r.templates, r.modTimes = templates, modTimes
+	r.mu.Unlock()
+	return nil
+}
+
+// Get 按引用取模板：name@version 取指定版本；只有 name 时取固定的版本，未固定则取最新版本
+func (r *Registry) Get(ref string) (*Template, error) {
+	name, version, _ := strings.Cut(ref, "@")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if version == "" {
+		version = r.pins[name]
+	}
+	versions := r.templates[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("prompt %q not found", name)
+	}
+	if version == "" || version == "latest" {
+		return versions[len(versions)-1], nil
+	}
+	for _, t := range versions {
+		if compareVersion(t.Version, version) == 0 {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("prompt %s@%s not found", name, version)
+}
+
+// Pin 固定某个模板的版本，version 为空表示取消固定
+func (r *Registry) Pin(name, version string) error {
+	if version != "" {
+		if _, err := r.Get(name + "@" + version); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if version == "" {
+		delete(r.pins, name)
+	} else {
+		r.pins[name] = version
+	}
+	return nil
+}
+
+// List 返回全部模板，按名称、版本排序
+func (r *Registry) List() []*Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out []*Template
+	for _, name := range names {
+		out = append(out, r.templates[name]...)
+	}
+	return out
+}
+
+// ParsePins 解析 "translator@1.0.0,code_reviewer@2" 形式的版本固定配置，便于从环境变量或命令行读取
+func ParsePins(s string) (map[string]string, error) {
+	pins := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, version, ok := strings.Cut(item, "@")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid pin %q, want name@version", item)
+		}
+		if _, err := parseVersion(version); err != nil {
+			return nil, fmt.Errorf("pin %q: %w", item, err)
+		}
+		pins[name] = version
+	}
+	return pins, nil
+}
+
+// watch 轮询目录，发现文件新增、删除或修改时重新加载；加载失败时保留旧模板并打日志
+func (r *Registry) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	lastErr := "" // 同一个错误只记一次日志，文件修好前不会刷屏
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.changed() {
+			continue
+		}
+		if err := r.Reload(); err != nil {
+			if err.Error() != lastErr {
+				lastErr = err.Error()
+				log.Printf("prompts: reload %s: %v", r.dir, err)
+			}
+			continue
+		}
+		lastErr = ""
+		log.Printf("prompts: reloaded %s", r.dir)
+	}
+}
+
+// changed 目录中的模板文件是否与上次加载时不同
+func (r *Registry) changed() bool {
+	r.mu.RLock()
+	known := r.modTimes
+	r.mu.RUnlock()
+
+	seen := 0
+	changed := false
+	_ = filepath.WalkDir(r.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		seen++
+		info, err := d.Info()
+		if t, ok := known[path]; err != nil || !ok || !t.Equal(info.ModTime()) {
+			changed = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return changed || seen != len(known)
+}
@@ -0,0 +1,220 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Message 模板中的一条消息
+type Message struct {
+	// Role system / user / assistant
+	Role    string `yaml:"role"`
+	Content string `yaml:"content"`
+	// Placeholder 非空时本条是 MessagesPlaceholder，渲染时插入该变量中的消息（如历史对话、few-shot 示例），
+	// 变量不存在时插入空列表
+	Placeholder string `yaml:"placeholder"`
+	// Media 多模态变量，仅用于 user 消息：渲染时依次展开为图片 / 文件片段，见 MediaMessage（不读取本地路径）
+	Media []string `yaml:"media"`
+}
+
+// Template 一个版本的提示词模板，对应一个 YAML 文件
+type Template struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description"`
+	// Format 变量语法：fstring（默认）、gotemplate、jinja2
+	Format string `yaml:"format"`
+	// Variables 变量声明；声明后加载时会校验模板中的占位符与声明一致
+	Variables []Variable `yaml:"variables"`
+	// Model 推荐使用的模型，仅作参考
+	Model    string    `yaml:"model"`
+	Messages []Message `yaml:"messages"`
+	// Examples few-shot 示例，配合 ExampleSelector 与 WithExamples 使用
+	Examples []Example `yaml:"examples"`
+
+	// Path 来源文件
+	Path string `yaml:"-"`
+}
+
+// Ref 形如 name@version 的引用
+func (t *Template) Ref() string {
+	return t.Name + "@" + t.Version
+}
+
+// LoadFile 读取并校验一个模板文件
+func LoadFile(path string) (*Template, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Template
+	if err := yaml.Unmarshal(b, &t); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	t.Path = path
+	if err := t.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &t, nil
+}
+
+func (t *Template) validate() error {
+	if t.Name == "" || strings.Contains(t.Name, "@") {
+		return fmt.Errorf("invalid template name %q", t.Name)
+	}
+	if _, err := parseVersion(t.Version); err != nil {
+		return err
+	}
+	if _, err := t.formatType(); err != nil {
+		return err
+	}
+	if len(t.Messages) == 0 {
+		return fmt.Errorf("template %s has no messages", t.Ref())
+	}
+	for i, m := range t.Messages {
+		if m.Placeholder != "" {
+			continue
+		}
+		switch schema.RoleType(m.Role) {
+		case schema.System, schema.User, schema.Assistant:
+		default:
+			return fmt.Errorf("message %d: unsupported role %q", i+1, m.Role)
+		}
+		if len(m.Media) > 0 && schema.RoleType(m.Role) != schema.User {
+			return fmt.Errorf("message %d: media is only supported in user messages", i+1)
+		}
+	}
+	report, err := t.Check(nil)
+	if err != nil {
+		return err
+	}
+	if len(t.Variables) > 0 {
+		return report.Err()
+	}
+	return nil
+}
+
+// Check 校验模板变量，values 为 nil 时只做静态检查；未声明变量时按模板中的占位符推断
+func (t *Template) Check(values map[string]any) (*Report, error) {
+	ft, err := t.formatType()
+	if err != nil {
+		return nil, err
+	}
+	return Check(ft, t.messageTemplates(), t.Variables, values)
+}
+
+func (t *Template) messageTemplates() []schema.MessagesTemplate {
+	msgs := make([]schema.MessagesTemplate, 0, len(t.Messages))
+	for _, m := range t.Messages {
+		if m.Placeholder != "" {
+			msgs = append(msgs, Placeholder(m.Placeholder, true))
+			continue
+		}
+		if len(m.Media) > 0 {
+			msgs = append(msgs, MediaMessage(m.Content, m.Media...))
+			continue
+		}
+		msgs = append(msgs, NewMessage(schema.RoleType(m.Role), m.Content))
+	}
+	return msgs
+}
+
+func (t *Template) formatType() (schema.FormatType, error) {
+	switch strings.ToLower(t.Format) {
+	case "", "fstring":
+		return schema.FString, nil
+	case "gotemplate", "go_template":
+		return schema.GoTemplate, nil
+	case "jinja2":
+		return schema.Jinja2, nil
+	default:
+		return 0, fmt.Errorf("unsupported template format %q", t.Format)
+	}
+}
+
+// ChatTemplate 转成 eino 的 ChatTemplate；Format 时先检查必需变量是否齐全、类型是否相符，gotemplate 格式可使用 Funcs
+func (t *Template) ChatTemplate() prompt.ChatTemplate {
+	ft, _ := t.formatType() // 已在加载时校验
+	return &chatTemplate{tpl: t, inner: prompt.FromMessages(ft, t.messageTemplates()...)}
+}
+
+type chatTemplate struct {
+	tpl   *Template
+	inner *prompt.DefaultChatTemplate
+}
+
+func (c *chatTemplate) Format(ctx context.Context, vs map[string]any, opts ...prompt.Option) ([]*schema.Message, error) {
+	// 多传的变量不算错误（例如 Bind 整个结构体），只检查缺失和类型
+	report := &Report{}
+	for _, v := range c.tpl.Variables {
+		value, ok := vs[v.Name]
+		if !ok {
+			if !v.Optional {
+				report.Missing = append(report.Missing, v.Name)
+			}
+			continue
+		}
+		if got, ok := checkType(v.Type, value); !ok {
+			report.Mistyped = append(report.Mistyped, Mistyped{Name: v.Name, Want: v.Type, Got: got})
+		}
+	}
+	if err := report.Err(); err != nil {
+		return nil, fmt.Errorf("prompt %s: %w", c.tpl.Ref(), err)
+	}
+	return c.inner.Format(ctx, vs, opts...)
+}
+
+func (c *chatTemplate) GetType() string {
+	return c.inner.GetType()
+}
+
+func (c *chatTemplate) IsCallbacksEnabled() bool {
+	return c.inner.IsCallbacksEnabled()
+}
+
+// parseVersion 解析 1 / 1.2 / 1.2.3 形式的版本号
+func parseVersion(v string) ([]int, error) {
+	v = strings.TrimPrefix(v, "v")
+	if v == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+// compareVersion 比较两个版本号，缺省位按 0 处理
+func compareVersion(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	for i := 0; i < max(len(va), len(vb)); i++ {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,427 @@
+package prompts
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/cloudwego/eino/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// VarType 模板变量的类型
+type VarType string
+
+const (
+	TypeAny      VarType = "any"
+	TypeString   VarType = "string"
+	TypeInt      VarType = "int"
+	TypeNumber   VarType = "number"
+	TypeBool     VarType = "bool"
+	TypeList     VarType = "list"
+	TypeMap      VarType = "map" // map 或结构体
+	TypeMessages VarType = "messages"
+	TypeMedia    VarType = "media" // 图片地址、[]byte 或 schema.MessageInputPart，MediaMessageIn 中还可以是本地路径
+)
+
+// Variable 模板变量声明
+// YAML 中可以只写变量名（必需、任意类型），也可以写成 {name: level, type: int, optional: true}
+type Variable struct {
+	Name        string  `yaml:"name"`
+	Type        VarType `yaml:"type"`
+	Optional    bool    `yaml:"optional"`
+	Description string  `yaml:"description"`
+}
+
+func (v *Variable) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		v.Name = node.Value
+		return nil
+	}
+	type plain Variable
+	return node.Decode((*plain)(v))
+}
+
+// Mistyped 类型不符的变量
+type Mistyped struct {
+	Name string
+	Want VarType
+	Got  string
+}
+
+// Report 变量校验结果
+type Report struct {
+	// Missing 必需变量没有传入
+	Missing []string
+	// Unused 声明了但模板中没有用到的变量，以及传入了但没有声明的变量
+	Unused []string
+	// Undeclared 模板中用到了但没有声明的变量
+	Undeclared []string
+	// Mistyped 传入的值与声明的类型不符
+	Mistyped []Mistyped
+	// Unsupported 无法静态分析的消息模板（如 schema.MessagesPlaceholder，请改用 Placeholder），其中的变量未参与校验
+	Unsupported []string
+}
+
+// OK 没有任何问题
+func (r *Report) OK() bool {
+	return len(r.Missing) == 0 && len(r.Unused) == 0 && len(r.Undeclared) == 0 && len(r.Mistyped) == 0 &&
+		len(r.Unsupported) == 0
+}
+
+// Err 有问题时返回描述全部问题的错误，否则返回 nil
+func (r *Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	return fmt.Errorf("invalid prompt variables: %s", r)
+}
+
+func (r *Report) String() string {
+	var parts []string
+	if len(r.Missing) > 0 {
+		parts = append(parts, "missing: "+strings.Join(r.Missing, ", "))
+	}
+	if len(r.Undeclared) > 0 {
+		parts = append(parts, "undeclared: "+strings.Join(r.Undeclared, ", "))
+	}
+	if len(r.Unused) > 0 {
+		parts = append(parts, "unused: "+strings.Join(r.Unused, ", "))
+	}
+	for _, m := range r.Mistyped {
+		parts = append(parts, fmt.Sprintf("%s: want %s, got %s", m.Name, m.Want, m.Got))
+	}
+	if len(r.Unsupported) > 0 {
+		parts = append(parts, "unsupported: "+strings.Join(r.Unsupported, ", "))
+	}
+	if len(parts) == 0 {
+		return "ok"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Check 静态校验模板变量：从模板中提取占位符，与声明的变量比对；values 非 nil 时再检查传入的值
+// vars 为 nil 时把模板中用到的变量都视为必需、任意类型的变量
+// 模板本身无法解析时返回错误；无法识别的消息模板记入 Report.Unsupported
+func Check(format schema.FormatType, templates []schema.MessagesTemplate, vars []Variable, values map[string]any) (*Report, error) {
+	used := make(map[string]bool)
+	var (
+		placeholders []Variable // Placeholder、MediaMessage 自带声明
+		unsupported  []string
+	)
+	for i, t := range templates {
+		content, decls, ok := templateContent(t)
+		if !ok {
+			unsupported = append(unsupported, fmt.Sprintf("message %d (%T)", i+1, t))
+			continue
+		}
+		for _, v := range decls {
+			used[v.Name] = true
+			placeholders = append(placeholders, v)
+		}
+		if content == "" {
+			continue
+		}
+		names, err := Placeholders(format, content)
+		if err != nil {
+			return nil, fmt.Errorf("message %d: %w", i+1, err)
+		}
+		for _, name := range names {
+			used[name] = true
+		}
+	}
+
+	// 显式声明优先，其次是 Placeholder 自带的声明，最后按占位符推断
+	declared := make(map[string]Variable)
+	for _, v := range placeholders {
+		declared[v.Name] = v
+	}
+	for _, v := range vars {
+		declared[v.Name] = v
+	}
+	if vars == nil {
+		for name := range used {
+			if _, ok := declared[name]; !ok {
+				declared[name] = Variable{Name: name}
+			}
+		}
+	}
+
+	r := &Report{Unsupported: unsupported}
+	for name := range used {
+		if _, ok := declared[name]; !ok {
+			r.Undeclared = append(r.Undeclared, name)
+		}
+	}
+	for name := range declared {
+		if !used[name] {
+			r.Unused = append(r.Unused, name)
+		}
+	}
+	if values != nil {
+		for name, v := range declared {
+			value, ok := values[name]
+			if !ok {
+				if !v.Optional {
+					r.Missing = append(r.Missing, name)
+				}
+				continue
+			}
+			if got, ok := checkType(v.Type, value); !ok {
+				r.Mistyped = append(r.Mistyped, Mistyped{Name: name, Want: v.Type, Got: got})
+			}
+		}
+		for name := range values {
+			if _, ok := declared[name]; !ok && !used[name] {
+				r.Unused = append(r.Unused, name)
+			}
+		}
+	}
+	sort.Strings(r.Missing)
+	sort.Strings(r.Unused)
+	sort.Strings(r.Undeclared)
+	sort.Slice(r.Mistyped, func(i, j int) bool { return r.Mistyped[i].Name < r.Mistyped[j].Name })
+	return r, nil
+}
+
+// templateContent 取出消息模板的文本，以及模板自带的变量声明（Placeholder 的 key、MediaMessage 的媒体变量）
+// 只认识本包与 schema.Message 的实现，其余返回 false
+func templateContent(t schema.MessagesTemplate) (content string, decls []Variable, ok bool) {
+	switch m := t.(type) {
+	case *schema.Message:
+		return m.Content, nil, true
+	case *funcMessage:
+		return m.content, nil, true
+	case *placeholderMessage:
+		return "", []Variable{{Name: m.key, Type: TypeMessages, Optional: m.optional}}, true
+	case *mediaMessage:
+		for _, key := range m.keys {
+			decls = append(decls, Variable{Name: key, Type: TypeMedia, Optional: true})
+		}
+		return m.text.content, decls, true
+	}
+	return "", nil, false
+}
+
+// checkType 值是否符合声明的类型，不符时返回实际类型
+func checkType(want VarType, value any) (string, bool) {
+	if value == nil {
+		return "nil", want == TypeAny || want == ""
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	got := v.Type().String()
+	switch want {
+	case TypeAny, "":
+		return got, true
+	case TypeString:
+		return got, v.Kind() == reflect.String
+	case TypeInt:
+		return got, v.CanInt() || v.CanUint()
+	case TypeNumber:
+		return got, v.CanInt() || v.CanUint() || v.CanFloat()
+	case TypeBool:
+		return got, v.Kind() == reflect.Bool
+	case TypeList:
+		return got, v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	case TypeMap:
+		return got, v.Kind() == reflect.Map || v.Kind() == reflect.Struct
+	case TypeMessages:
+		_, ok := value.([]*schema.Message)
+		return got, ok
+	case TypeMedia:
+		return got, isMedia(value)
+	default:
+		return got, false
+	}
+}
+
+// Placeholders 提取模板中引用的顶层变量名（去重、排序）
+func Placeholders(format schema.FormatType, content string) ([]string, error) {
+	var (
+		names []string
+		err   error
+	)
+	switch format {
+	case schema.FString:
+		names, err = fstringNames(content)
+	case schema.GoTemplate:
+		names, err = goTemplateNames(content)
+	case schema.Jinja2:
+		names = jinjaNames(content)
+	default:
+		return nil, fmt.Errorf("unknown format type: %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// fstringNames 解析 {name}、{name.attr}、{name[0]}、{name:>10}，{{ 与 }} 为转义
+func fstringNames(s string) ([]string, error) {
+	var names []string
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			if i+1 < len(s) && s[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at offset %d", i)
+			}
+			field := s[i+1 : i+end]
+			if cut := strings.IndexAny(field, ".[:!"); cut >= 0 {
+				field = field[:cut]
+			}
+			if field = strings.TrimSpace(field); field == "" {
+				return nil, fmt.Errorf("empty placeholder at offset %d", i)
+			}
+			names = append(names, field)
+			i += end
+		case '}':
+			if i+1 < len(s) && s[i+1] == '}' {
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("single '}' at offset %d", i)
+		}
+	}
+	return names, nil
+}
+
+// goTemplateNames 遍历模板语法树，收集以根上下文访问的字段（.name、$.name）
+// range / with 内部的 . 已不是根上下文，其中的字段不计入
+func goTemplateNames(s string) ([]string, error) {
+	tpl, err := template.New("prompt").Funcs(Funcs).Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	var walk func(node parse.Node, root bool)
+	walk = func(node parse.Node, root bool) {
+		switch n := node.(type) {
+		case nil:
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c, root)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, root)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c, root)
+			}
+		case *parse.CommandNode:
+			for _, a := range n.Args {
+				walk(a, root)
+			}
+		case *parse.FieldNode:
+			if root {
+				names = append(names, n.Ident[0])
+			}
+		case *parse.ChainNode:
+			walk(n.Node, root)
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				names = append(names, n.Ident[1])
+			}
+		case *parse.IfNode:
+			walk(n.Pipe, root)
+			walk(n.List, root)
+			walk(n.ElseList, root)
+		case *parse.RangeNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.WithNode:
+			walk(n.Pipe, root)
+			walk(n.List, false)
+			walk(n.ElseList, root)
+		case *parse.TemplateNode:
+			walk(n.Pipe, root)
+		}
+	}
+	walk(tpl.Tree.Root, true)
+	return names, nil
+}
+
+var (
+	jinjaBlockRe   = regexp.MustCompile(`(?s)\{\{-?(.*?)-?\}\}|\{%-?(.*?)-?%\}`)
+	jinjaCommentRe = regexp.MustCompile(`(?s)\{#.*?#\}`)
+	jinjaStringRe  = regexp.MustCompile(`"(?:\\.|[^"\\])*"|'(?:\\.|[^'\\])*'`)
+	jinjaIdentRe   = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	jinjaLocalRe   = regexp.MustCompile(`^\s*(?:for\s+([\w\s,]+?)\s+in\b|set\s+(\w+)\s*=)`)
+)
+
+var jinjaKeywords = map[string]bool{
+	"if": true, "elif": true, "else": true, "endif": true, "for": true, "endfor": true, "in": true,
+	"not": true, "and": true, "or": true, "is": true, "set": true, "endset": true, "recursive": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true, "loop": true,
+	"with": true, "endwith": true, "filter": true, "endfilter": true, "raw": true, "endraw": true,
+	"macro": true, "endmacro": true, "call": true, "endcall": true, "block": true, "endblock": true,
+}
+
+// jinjaNames 从 {{ }} 与 {% %} 中提取变量名
+// 排除关键字、过滤器与测试名、属性访问、函数调用、关键字参数，以及 for / set 定义的局部变量
+func jinjaNames(s string) []string {
+	s = jinjaCommentRe.ReplaceAllString(s, "")
+	locals := make(map[string]bool)
+	var exprs []string
+	for _, m := range jinjaBlockRe.FindAllStringSubmatch(s, -1) {
+		expr := jinjaStringRe.ReplaceAllString(m[1]+m[2], `""`)
+		if lm := jinjaLocalRe.FindStringSubmatch(expr); lm != nil {
+			for _, name := range strings.Split(lm[1]+","+lm[2], ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					locals[name] = true
+				}
+			}
+			// 局部变量定义本身不算引用，只看 in / = 之后的表达式
+			expr = expr[len(lm[0]):]
+		}
+		exprs = append(exprs, expr)
+	}
+
+	var names []string
+	for _, expr := range exprs {
+		for _, loc := range jinjaIdentRe.FindAllStringIndex(expr, -1) {
+			name := expr[loc[0]:loc[1]]
+			before := strings.TrimRight(expr[:loc[0]], " \t\n")
+			after := strings.TrimLeft(expr[loc[1]:], " \t\n")
+			switch {
+			case jinjaKeywords[name] || locals[name]:
+			case loc[0] > 0 && (expr[loc[0]-1] >= '0' && expr[loc[0]-1] <= '9'):
+			case strings.HasSuffix(before, ".") || strings.HasSuffix(before, "|"):
+			case strings.HasSuffix(before, " is") || strings.HasSuffix(before, " is not") || before == "is":
+			case strings.HasPrefix(after, "("):
+			case strings.HasPrefix(after, "=") && !strings.HasPrefix(after, "=="):
+			default:
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ChatModel 带限流的 ChatModel 装饰器
+// 请求前按输入估算 token 预占配额，响应返回后按实际用量修正
+type ChatModel struct {
+	inner   model.BaseChatModel
+	limiter *Limiter
+}
+
+var _ model.ToolCallingChatModel = (*ChatModel)(nil)
+
+// NewChatModel 用限流器包装一个 ChatModel；多个模型共享同一个 Limiter 即共享配额
+func NewChatModel(inner model.BaseChatModel, limiter *Limiter) (*ChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("inner chat model is required")
+	}
+	if limiter == nil {
+		return nil, errors.New("limiter is required")
+	}
+	return &ChatModel{inner: inner, limiter: limiter}, nil
+}
+
+func (c *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	estimate := estimateMessages(input, opts...)
+	if err := c.limiter.Wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+	out, err := c.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if usage := messageUsage(out); usage != nil {
+		c.limiter.Adjust(usage.TotalTokens - estimate)
+	}
+	return out, nil
+}
+
+func (c *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	estimate := estimateMessages(input, opts...)
+	if err := c.limiter.Wait(ctx, estimate); err != nil {
+		return nil, err
+	}
+	sr, err := c.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转发分片的同时记下最后一次出现的用量，流结束后修正预占
+	out, w := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer sr.Close()
+		defer w.Close()
+		var usage *schema.TokenUsage
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if u := messageUsage(chunk); u != nil {
+				usage = u
+			}
+			if closed := w.Send(chunk, err); closed || err != nil {
+				break
+			}
+		}
+		if usage != nil {
+			c.limiter.Adjust(usage.TotalTokens - estimate)
+		}
+	}()
+	return out, nil
+}
+
+// WithTools 返回绑定了工具的新实例，与原实例共享同一个 Limiter
+func (c *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tc, ok := c.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, errors.New("inner chat model does not implement ToolCallingChatModel")
+	}
+	inner, err := tc.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &ChatModel{inner: inner, limiter: c.limiter}, nil
+}
+
+// GetType 沿用内部模型的类型，trace / metrics 中仍显示真实模型
+func (c *ChatModel) GetType() string {
+	typ, _ := components.GetType(c.inner)
+	return typ
+}
+
+// IsCallbacksEnabled 与内部模型保持一致，避免回调重复触发
+func (c *ChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.inner)
+}
+
+// estimateMessages 预估本次请求的 token：输入消息 + 设置了 MaxTokens 时的输出上限
+func estimateMessages(input []*schema.Message, opts ...model.Option) int {
+	n := 0
+	for _, m := range input {
+		if m == nil {
+			continue
+		}
+		n += 4 + EstimateTokens(m.Content) // 每条消息约有 4 个 token 的角色/分隔开销
+		for _, tc := range m.ToolCalls {
+			n += EstimateTokens(tc.Function.Name) + EstimateTokens(tc.Function.Arguments)
+		}
+	}
+	if common := model.GetCommonOptions(&model.Options{}, opts...); common.MaxTokens != nil {
+		n += *common.MaxTokens
+	}
+	return n
+}
+
+func messageUsage(msg *schema.Message) *schema.TokenUsage {
+	if msg == nil || msg.ResponseMeta == nil {
+		return nil
+	}
+	return msg.ResponseMeta.Usage
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+)
+
+// Embedder 带限流的 Embedder 装饰器，每次 EmbedStrings 计 1 个请求，token 按全部文本估算
+type Embedder struct {
+	inner   embedding.Embedder
+	limiter *Limiter
+}
+
+var _ embedding.Embedder = (*Embedder)(nil)
+
+// NewEmbedder 用限流器包装一个 Embedder
+func NewEmbedder(inner embedding.Embedder, limiter *Limiter) (*Embedder, error) {
+	if inner == nil {
+		return nil, errors.New("inner embedder is required")
+	}
+	if limiter == nil {
+		return nil, errors.New("limiter is required")
+	}
+	return &Embedder{inner: inner, limiter: limiter}, nil
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	n := 0
+	for _, text := range texts {
+		n += EstimateTokens(text)
+	}
+	if err := e.limiter.Wait(ctx, n); err != nil {
+		return nil, err
+	}
+	return e.inner.EmbedStrings(ctx, texts, opts...)
+}
+
+// GetType 沿用内部 Embedder 的类型
+func (e *Embedder) GetType() string {
+	typ, _ := components.GetType(e.inner)
+	return typ
+}
+
+// IsCallbacksEnabled 与内部 Embedder 保持一致，避免回调重复触发
+func (e *Embedder) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(e.inner)
+}
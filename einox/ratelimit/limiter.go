@@ -0,0 +1,199 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Config 限流配置，对应服务商的 RPM / TPM 配额
+type Config struct {
+	// Name 限流器名称，用作指标标签，默认 default
+	Name string
+	// RequestsPerMinute 每分钟请求数，0 表示不限
+	RequestsPerMinute int
+	// TokensPerMinute 每分钟 token 数，0 表示不限
+	TokensPerMinute int
+}
+
+// Limiter 请求数与 token 数两个令牌桶组成的限流器，可在多个 goroutine、多个模型间共享
+// 桶容量为每分钟配额，按配额/60 每秒匀速补充
+type Limiter struct {
+	name     string
+	requests *bucket
+	tokens   *bucket
+
+	mu       sync.Mutex
+	waiting  int
+	waitTime time.Duration
+}
+
+// New 创建限流器
+func New(cfg *Config) *Limiter {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	name := cfg.Name
+	if name == "" {
+		name = "default"
+	}
+	return &Limiter{
+		name:     name,
+		requests: newBucket(cfg.RequestsPerMinute),
+		tokens:   newBucket(cfg.TokensPerMinute),
+	}
+}
+
+// Name 限流器名称
+func (l *Limiter) Name() string {
+	return l.name
+}
+
+// Wait 为一次请求预占 1 个请求配额和 tokens 个 token 配额，配额不足时阻塞等待
+// ctx 取消或超时时立即返回 ctx.Err()，并归还已预占的配额
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := time.Now()
+	d1 := l.requests.reserve(now, 1)
+	d2 := l.tokens.reserve(now, tokens)
+	delay := max(d1, d2)
+	if delay <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.waiting++
+	l.mu.Unlock()
+	start := time.Now()
+	defer func() {
+		l.mu.Lock()
+		l.waiting--
+		l.waitTime += time.Since(start)
+		l.mu.Unlock()
+	}()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.requests.refund(1)
+		l.tokens.refund(tokens)
+		return ctx.Err()
+	}
+}
+
+// Adjust 拿到实际用量后修正 token 预占：delta = 实际 - 预估，为负时归还
+func (l *Limiter) Adjust(delta int) {
+	if delta > 0 {
+		l.tokens.reserve(time.Now(), delta)
+	} else if delta < 0 {
+		l.tokens.refund(-delta)
+	}
+}
+
+// Stats 限流器当前状态
+type Stats struct {
+	// RequestUtilization / TokenUtilization 桶的占用比例，超过 1 表示有请求在排队
+	RequestUtilization float64
+	TokenUtilization   float64
+	// Waiting 正在等待配额的调用数
+	Waiting int
+	// WaitTime 累计等待时长
+	WaitTime time.Duration
+}
+
+// Stats 返回当前利用率等状态
+func (l *Limiter) Stats() Stats {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{
+		RequestUtilization: l.requests.utilization(now),
+		TokenUtilization:   l.tokens.utilization(now),
+		Waiting:            l.waiting,
+		WaitTime:           l.waitTime,
+	}
+}
+
+// bucket 令牌桶；available 可以为负，表示已被排队的请求预占
+type bucket struct {
+	mu        sync.Mutex
+	capacity  float64
+	perSecond float64
+	available float64
+	last      time.Time
+}
+
+func newBucket(perMinute int) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{
+		capacity:  float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		available: float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.available = math.Min(b.capacity, b.available+elapsed.Seconds()*b.perSecond)
+		b.last = now
+	}
+}
+
+// reserve 预占 n 个令牌，返回需要等待的时长
+// 单次请求超过桶容量时按容量计，否则永远等不到
+func (b *bucket) reserve(now time.Time, n int) time.Duration {
+	if b == nil || n <= 0 {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.available -= math.Min(float64(n), b.capacity)
+	if b.available >= 0 {
+		return 0
+	}
+	return time.Duration(-b.available / b.perSecond * float64(time.Second))
+}
+
+func (b *bucket) refund(n int) {
+	if b == nil || n <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.available = math.Min(b.capacity, b.available+math.Min(float64(n), b.capacity))
+}
+
+func (b *bucket) utilization(now time.Time) float64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	return 1 - b.available/b.capacity
+}
+
+// EstimateTokens 粗略估算文本的 token 数：ASCII 约 4 字符 1 个 token，其余字符（中日韩等）约 1 字 1 个
+// 只用于请求前预占配额，实际用量返回后会通过 Adjust 修正
+func EstimateTokens(text string) int {
+	ascii, other := 0, 0
+	for _, r := range text {
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+	}
+	return (ascii+3)/4 + other
+}
@@ -0,0 +1,103 @@
+package router
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/NuyoahCh/einotelos/einox/prompteval"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RouteStats 一条路由的累计用量
+type RouteStats struct {
+	Route string `json:"route"`
+	Model string `json:"model"`
+	Calls int    `json:"calls"`
+	// CachedCalls 命中响应缓存的调用，计入 Calls 但不计 token 与成本
+	CachedCalls      int     `json:"cached_calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+	// BaselineCost 同样的 token 用量按基准模型计价的成本
+	BaselineCost float64 `json:"baseline_cost"`
+}
+
+// record 累计一次调用；缓存回放的是当初的用量，并没有真正请求模型，只计调用次数
+func (r *Router) record(route *Route, usage *schema.TokenUsage, cached bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.stats[route.Name]
+	if !ok {
+		s = &RouteStats{Route: route.Name, Model: route.ModelName}
+		r.stats[route.Name] = s
+	}
+	s.Calls++
+	if cached {
+		s.CachedCalls++
+		return
+	}
+	if usage == nil {
+		return
+	}
+	s.PromptTokens += usage.PromptTokens
+	s.CompletionTokens += usage.CompletionTokens
+	s.Cost += priceOf(route).cost(usage)
+	s.BaselineCost += priceOf(r.baseline).cost(usage)
+}
+
+// Stats 按路由声明顺序返回累计用量的副本
+func (r *Router) Stats() []RouteStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []RouteStats
+	for _, route := range r.order {
+		if s, ok := r.stats[route.Name]; ok {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// WriteReport 输出各路由的调用量与成本，并与全部使用基准模型时的成本对比
+// 基准成本按实际 token 用量换算，不同模型的输出长度差异不计入；命中缓存的调用单列，不计成本
+func (r *Router) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTE\tMODEL\tCALLS\tCACHED\tPROMPT\tCOMPLETION\tCOST($)\tBASELINE($)")
+	var total RouteStats
+	for _, s := range r.Stats() {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%.6f\t%.6f\n",
+			s.Route, s.Model, s.Calls, s.CachedCalls, s.PromptTokens, s.CompletionTokens, s.Cost, s.BaselineCost)
+		total.Calls += s.Calls
+		total.CachedCalls += s.CachedCalls
+		total.PromptTokens += s.PromptTokens
+		total.CompletionTokens += s.CompletionTokens
+		total.Cost += s.Cost
+		total.BaselineCost += s.BaselineCost
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t%d\t%d\t%d\t%.6f\t%.6f\n",
+		total.Calls, total.CachedCalls, total.PromptTokens, total.CompletionTokens, total.Cost, total.BaselineCost)
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if total.BaselineCost > 0 {
+		diff := total.Cost - total.BaselineCost
+		_, err := fmt.Fprintf(w, "相对全部使用 %s（%s）：%+.6f 美元（%+.1f%%）\n",
+			r.baseline.Name, r.baseline.ModelName, diff, diff/total.BaselineCost*100)
+		return err
+	}
+	return nil
+}
+
+type price prompteval.Price
+
+func priceOf(route *Route) price {
+	if route.Price != nil {
+		return price(*route.Price)
+	}
+	return price(prompteval.Prices[route.ModelName])
+}
+
+func (p price) cost(u *schema.TokenUsage) float64 {
+	return (float64(u.PromptTokens)*p.Input + float64(u.CompletionTokens)*p.Output) / 1e6
+}
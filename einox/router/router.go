@@ -0,0 +1,323 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/NuyoahCh/einotelos/einox/cache"
+	"github.com/NuyoahCh/einotelos/einox/prompteval"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Route 一条路由目标：模型 + 生成参数
+type Route struct {
+	// Name 路由名，规则与分类模型通过它选择路由
+	Name string
+	// Description 路由适合的任务，供分类模型参考
+	Description string
+	Model       model.BaseChatModel
+	// ModelName 模型名，用于日志与查价
+	ModelName string
+	// Options 该路由默认的生成参数，调用方传入的 Option 优先
+	Options []model.Option
+	// Price 为空时按 ModelName 查 prompteval.Prices
+	Price *prompteval.Price
+}
+
+// Rule 路由规则：Task 与 Pattern 都设置时需同时满足
+type Rule struct {
+	// Task 任务名，与 WithTask 设置的值相等即命中；以 * 结尾表示前缀匹配
+	Task string
+	// Pattern 匹配最后一条用户消息
+	Pattern *regexp.Regexp
+	Route   string
+}
+
+func (r *Rule) match(task, query string) bool {
+	if r.Task == "" && r.Pattern == nil {
+		return false
+	}
+	if r.Task != "" {
+		if prefix, ok := strings.CutSuffix(r.Task, "*"); ok {
+			if !strings.HasPrefix(task, prefix) {
+				return false
+			}
+		} else if task != r.Task {
+			return false
+		}
+	}
+	return r.Pattern == nil || r.Pattern.MatchString(query)
+}
+
+// Config 路由配置
+type Config struct {
+	Routes []*Route
+	// Rules 按顺序匹配，第一条命中的生效
+	Rules []Rule
+	// Classifier 可选：没有规则命中时让模型从路由中选择，同一任务的结论会被缓存
+	Classifier model.BaseChatModel
+	// Default 兜底路由，默认第一条路由
+	Default string
+	// Baseline 成本对比的基准路由，即不做路由时所有请求都使用的模型，默认同 Default
+	Baseline string
+	// OnDecision 每次路由后调用，默认输出日志
+	OnDecision func(ctx context.Context, d *Decision)
+}
+
+// Decision 一次路由结论
+type Decision struct {
+	Task  string `json:"task,omitempty"`
+	Route string `json:"route"`
+	Model string `json:"model"`
+	// By 决策来源：rule / classifier / default
+	By     string `json:"by"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type taskKey struct{}
+
+// WithTask 标记本次调用的任务，如模板名 translator；规则与分类模型据此选择路由
+func WithTask(ctx context.Context, task string) context.Context {
+	return context.WithValue(ctx, taskKey{}, task)
+}
+
+// TaskFrom 读取 WithTask 设置的任务名
+func TaskFrom(ctx context.Context) string {
+	task, _ := ctx.Value(taskKey{}).(string)
+	return task
+}
+
+// Router 按任务把请求分发到不同的模型
+type Router struct {
+	routes     map[string]*Route
+	order      []*Route
+	rules      []Rule
+	classifier model.BaseChatModel
+	def        *Route
+	baseline   *Route
+	onDecision func(ctx context.Context, d *Decision)
+
+	mu        sync.Mutex
+	classSeen map[string]*Decision
+	stats     map[string]*RouteStats
+}
+
+var _ model.BaseChatModel = (*Router)(nil)
+
+// New 创建路由
+func New(cfg *Config) (*Router, error) {
+	if cfg == nil || len(cfg.Routes) == 0 {
+		return nil, errors.New("at least one route is required")
+	}
+	r := &Router{
+		routes:     make(map[string]*Route, len(cfg.Routes)),
+		order:      cfg.Routes,
+		rules:      cfg.Rules,
+		classifier: cfg.Classifier,
+		onDecision: cfg.OnDecision,
+		classSeen:  make(map[string]*Decision),
+		stats:      make(map[string]*RouteStats),
+	}
+	for _, route := range cfg.Routes {
+		if route.Name == "" || route.Model == nil {
+			return nil, errors.New("route name and model are required")
+		}
+		if _, ok := r.routes[route.Name]; ok {
+			return nil, fmt.Errorf("duplicate route %q", route.Name)
+		}
+		r.routes[route.Name] = route
+	}
+	for _, rule := range cfg.Rules {
+		if _, ok := r.routes[rule.Route]; !ok {
+			return nil, fmt.Errorf("rule %q: unknown route %q", rule.Task, rule.Route)
+		}
+	}
+
+	var ok bool
+	r.def = cfg.Routes[0]
+	if cfg.Default != "" {
+		if r.def, ok = r.routes[cfg.Default]; !ok {
+			return nil, fmt.Errorf("unknown default route %q", cfg.Default)
+		}
+	}
+	r.baseline = r.def
+	if cfg.Baseline != "" {
+		if r.baseline, ok = r.routes[cfg.Baseline]; !ok {
+			return nil, fmt.Errorf("unknown baseline route %q", cfg.Baseline)
+		}
+	}
+	if r.onDecision == nil {
+		r.onDecision = func(_ context.Context, d *Decision) {
+			log.Printf("[router] task=%q -> %s (%s) by %s %s", d.Task, d.Route, d.Model, d.By, d.Reason)
+		}
+	}
+	return r, nil
+}
+
+func (r *Router) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	route, err := r.route(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	out, err := route.Model.Generate(ctx, input, append(append([]model.Option(nil), route.Options...), opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	r.record(route, usageOf(out), cache.IsHit(out))
+	return out, nil
+}
+
+func (r *Router) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	route, err := r.route(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	sr, err := route.Model.Stream(ctx, input, append(append([]model.Option(nil), route.Options...), opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 转发分片的同时记下最后一次出现的用量与是否为缓存回放，流结束后计入统计
+	out, w := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer sr.Close()
+		defer w.Close()
+		var (
+			usage  *schema.TokenUsage
+			cached bool
+		)
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if u := usageOf(chunk); u != nil {
+				usage = u
+			}
+			cached = cached || cache.IsHit(chunk)
+			if closed := w.Send(chunk, err); closed || err != nil {
+				break
+			}
+		}
+		r.record(route, usage, cached)
+	}()
+	return out, nil
+}
+
+// GetType 路由本身不触发回调，trace / metrics 中显示被选中的真实模型
+func (r *Router) GetType() string {
+	return "Router"
+}
+
+// IsCallbacksEnabled 回调由被选中的模型触发，避免重复
+func (r *Router) IsCallbacksEnabled() bool {
+	return true
+}
+
+// route 依次尝试规则、分类模型、默认路由
+func (r *Router) route(ctx context.Context, input []*schema.Message) (*Route, error) {
+	task, query := TaskFrom(ctx), lastUserContent(input)
+	d := &Decision{Task: task, By: "default", Route: r.def.Name}
+	matched := false
+	for i := range r.rules {
+		if r.rules[i].match(task, query) {
+			d.By, d.Route, d.Reason = "rule", r.rules[i].Route, describeRule(&r.rules[i])
+			matched = true
+			break
+		}
+	}
+	if !matched && r.classifier != nil {
+		cd, err := r.classify(ctx, task, query)
+		if err != nil {
+			return nil, fmt.Errorf("router classifier: %w", err)
+		}
+		if cd != nil {
+			d = cd
+		}
+	}
+	route := r.routes[d.Route]
+	d.Model = route.ModelName
+	r.onDecision(ctx, d)
+	return route, nil
+}
+
+// classify 让分类模型从路由描述中选择；有任务名时同一任务只问一次
+func (r *Router) classify(ctx context.Context, task, query string) (*Decision, error) {
+	if task != "" {
+		r.mu.Lock()
+		cached, ok := r.classSeen[task]
+		r.mu.Unlock()
+		if ok {
+			c := *cached
+			return &c, nil
+		}
+	}
+
+	var b strings.Builder
+	for _, route := range r.order {
+		fmt.Fprintf(&b, "- %s: %s\n", route.Name, route.Description)
+	}
+	resp, err := r.classifier.Generate(ctx, []*schema.Message{
+		schema.SystemMessage("你负责为请求选择合适的模型路由。可选路由：\n" + b.String() +
+			"只输出一行：路由名|一句话理由。"),
+		schema.UserMessage(fmt.Sprintf("任务：%s\n请求：%s", task, truncate(query, 800))),
+	})
+	if err != nil {
+		return nil, err
+	}
+	name, reason, _ := strings.Cut(strings.TrimSpace(resp.Content), "|")
+	name = strings.Trim(strings.TrimSpace(name), "`\"'")
+	if _, ok := r.routes[name]; !ok {
+		// 分类模型给出未知路由时走默认路由，不中断请求
+		return nil, nil
+	}
+	d := &Decision{Task: task, Route: name, By: "classifier", Reason: strings.TrimSpace(reason)}
+	if task != "" {
+		r.mu.Lock()
+		r.classSeen[task] = d
+		r.mu.Unlock()
+	}
+	c := *d
+	return &c, nil
+}
+
+func describeRule(rule *Rule) string {
+	var parts []string
+	if rule.Task != "" {
+		parts = append(parts, "task="+rule.Task)
+	}
+	if rule.Pattern != nil {
+		parts = append(parts, "pattern="+rule.Pattern.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func lastUserContent(input []*schema.Message) string {
+	for i := len(input) - 1; i >= 0; i-- {
+		if input[i] != nil && input[i].Role == schema.User {
+			return input[i].Content
+		}
+	}
+	return ""
+}
+
+func usageOf(msg *schema.Message) *schema.TokenUsage {
+	if msg == nil || msg.ResponseMeta == nil {
+		return nil
+	}
+	return msg.ResponseMeta.Usage
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/NuyoahCh/einotelos/einox/metrics"
+	"github.com/cloudwego/eino/callbacks"
+)
+
+// runServe 启动 einox 服务，暴露 /metrics 与 /healthz
+// 只采集本进程内的 Eino 组件调用；实验程序在各自进程中通过 metrics.ServeFromEnv 暴露指标
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "监听地址")
+	_ = fs.Parse(args)
+
+	reg := metrics.NewRegistry()
+	m := metrics.New(reg)
+	callbacks.AppendGlobalHandlers(m.Handler())
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.HTTPHandler(reg))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("einox 服务已启动: http://localhost%s/metrics", *addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	log.Println("einox 服务正在关闭...")
+	return srv.Shutdown(shutdownCtx)
+}
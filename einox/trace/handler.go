@@ -0,0 +1,425 @@
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config Handler 配置
+type Config struct {
+	// Dir trace 文件目录，每次执行写入 {Dir}/{run_id}.jsonl，默认 ./traces
+	Dir string
+}
+
+// Handler 基于 Eino callbacks 的执行追踪器
+// 挂到 Chain / Graph / Workflow 上（compose.WithCallbacks 或 callbacks.AppendGlobalHandlers），
+// 每个组件执行结束后写一行 Span 到对应 run 的 JSONL 文件
+type Handler struct {
+	dir string
+
+	mu   sync.Mutex
+	runs map[string]*runFile
+}
+
+// runFile 一次执行对应的 trace 文件，所有 Span 结束后关闭
+type runFile struct {
+	f        *os.File
+	active   int
+	children sync.WaitGroup // 非根 Span，根 Span 结束前等待它们写完
+}
+
+// childWaitTimeout 根 Span 等待子 Span（主要是流式输出的收集协程）写完的最长时间
+const childWaitTimeout = 5 * time.Second
+
+// activeSpan 执行中的 Span
+type activeSpan struct {
+	Span
+
+	mu      sync.Mutex
+	pending sync.WaitGroup // 流式输入/输出的收集协程
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// NewHandler 创建一个新的 trace Handler
+func NewHandler(cfg *Config) (*Handler, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	dir := cfg.Dir
+	if dir == "" {
+		dir = "traces"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Handler{dir: dir, runs: map[string]*runFile{}}, nil
+}
+
+// Dir 返回 trace 文件目录
+func (h *Handler) Dir() string {
+	return h.dir
+}
+
+// Path 返回指定 run 的 trace 文件路径
+func (h *Handler) Path(runID string) string {
+	return filepath.Join(h.dir, runID+".jsonl")
+}
+
+// Close 关闭所有尚未结束的 run 文件
+func (h *Handler) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var errs []error
+	for id, rf := range h.runs {
+		errs = append(errs, rf.f.Close())
+		delete(h.runs, id)
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler) OnStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	ctx, s := h.start(ctx, info)
+	if s == nil {
+		return ctx
+	}
+	s.Input = encodeInput(info, input)
+	return ctx
+}
+
+func (h *Handler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	s, ok := ctx.Value(spanKey{}).(*activeSpan)
+	if !ok {
+		return ctx
+	}
+	s.mu.Lock()
+	s.Output, s.Usage = encodeOutput(info, output)
+	s.mu.Unlock()
+	h.finish(s)
+	return ctx
+}
+
+func (h *Handler) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	s, ok := ctx.Value(spanKey{}).(*activeSpan)
+	if !ok {
+		return ctx
+	}
+	s.mu.Lock()
+	s.Error = err.Error()
+	s.mu.Unlock()
+	h.finish(s)
+	return ctx
+}
+
+func (h *Handler) OnStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+
+	ctx, s := h.start(ctx, info)
+	if s == nil {
+		input.Close()
+		return ctx
+	}
+	s.Stream = true
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		chunks, err := drain(input)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.Input = encodeChunks(chunks)
+		if err != nil && s.Error == "" {
+			s.Error = err.Error()
+		}
+	}()
+	return ctx
+}
+
+func (h *Handler) OnEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+
+	s, ok := ctx.Value(spanKey{}).(*activeSpan)
+	if !ok {
+		output.Close()
+		return ctx
+	}
+	go func() {
+		chunks, err := drain(output)
+		s.mu.Lock()
+		s.Stream = true
+		s.Output, s.Usage = encodeStreamOutput(info, chunks)
+		if err != nil && s.Error == "" {
+			s.Error = err.Error()
+		}
+		s.mu.Unlock()
+		h.finish(s)
+	}()
+	return ctx
+}
+
+// start 创建 Span 并挂到 ctx 上，父 Span 取自 ctx
+func (h *Handler) start(ctx context.Context, info *callbacks.RunInfo) (context.Context, *activeSpan) {
+	if info == nil {
+		return ctx, nil
+	}
+	s := &activeSpan{Span: Span{
+		SpanID:    randomHex(8),
+		Name:      info.Name,
+		Type:      info.Type,
+		Component: string(info.Component),
+		StartTime: time.Now(),
+	}}
+	if parent, ok := ctx.Value(spanKey{}).(*activeSpan); ok {
+		s.RunID = parent.RunID
+		s.ParentID = parent.SpanID
+	} else if id, ok := ctx.Value(runIDKey{}).(string); ok && id != "" {
+		s.RunID = id
+	} else {
+		s.RunID = NewRunID()
+	}
+
+	h.mu.Lock()
+	rf, ok := h.runs[s.RunID]
+	if !ok {
+		f, err := os.OpenFile(h.Path(s.RunID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			h.mu.Unlock()
+			return ctx, nil
+		}
+		rf = &runFile{f: f}
+		h.runs[s.RunID] = rf
+	}
+	rf.active++
+	if s.ParentID != "" {
+		rf.children.Add(1)
+	}
+	h.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// finish 等待流式收集完成后写出 Span；run 内所有 Span 结束后关闭文件
+// 根 Span 会先等子 Span 写完，保证 Invoke 返回时 trace 文件已完整
+func (h *Handler) finish(s *activeSpan) {
+	s.pending.Wait()
+	if s.ParentID == "" {
+		h.mu.Lock()
+		rf, ok := h.runs[s.RunID]
+		h.mu.Unlock()
+		if ok {
+			waitTimeout(&rf.children, childWaitTimeout)
+		}
+	}
+
+	s.mu.Lock()
+	s.EndTime = time.Now()
+	s.LatencyMS = s.EndTime.Sub(s.StartTime).Milliseconds()
+	line, err := json.Marshal(&s.Span)
+	s.mu.Unlock()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	rf, ok := h.runs[s.RunID]
+	if !ok {
+		return
+	}
+	if err == nil {
+		_, _ = rf.f.Write(append(line, '\n'))
+	}
+	if s.ParentID != "" {
+		rf.children.Done()
+	}
+	rf.active--
+	if rf.active <= 0 {
+		_ = rf.f.Close()
+		delete(h.runs, s.RunID)
+	}
+}
+
+func waitTimeout(wg *sync.WaitGroup, d time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(d):
+	}
+}
+
+func drain[T any](sr *schema.StreamReader[T]) ([]T, error) {
+	defer sr.Close()
+	var chunks []T
+	for {
+		c, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, c)
+	}
+}
+
+// modelInput ChatModel 的输入摘要
+type modelInput struct {
+	Messages []*schema.Message `json:"messages"`
+	Tools    []string          `json:"tools,omitempty"`
+	Config   *model.Config     `json:"config,omitempty"`
+}
+
+// embeddingOutput Embedding 的输出摘要，向量本身不落盘
+type embeddingOutput struct {
+	Count      int `json:"count"`
+	Dimensions int `json:"dimensions"`
+}
+
+func encodeInput(info *callbacks.RunInfo, input callbacks.CallbackInput) json.RawMessage {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if in := model.ConvCallbackInput(input); in != nil {
+			mi := modelInput{Messages: in.Messages, Config: in.Config}
+			for _, t := range in.Tools {
+				mi.Tools = append(mi.Tools, t.Name)
+			}
+			return encodeValue(mi)
+		}
+	case components.ComponentOfTool:
+		if in := tool.ConvCallbackInput(input); in != nil {
+			return encodeValue(in.ArgumentsInJSON)
+		}
+	case components.ComponentOfRetriever:
+		if in := retriever.ConvCallbackInput(input); in != nil {
+			return encodeValue(in)
+		}
+	case components.ComponentOfEmbedding:
+		if in := embedding.ConvCallbackInput(input); in != nil {
+			return encodeValue(in.Texts)
+		}
+	case components.ComponentOfPrompt:
+		if in := prompt.ConvCallbackInput(input); in != nil {
+			return encodeValue(in.Variables)
+		}
+	}
+	return encodeValue(input)
+}
+
+func encodeOutput(info *callbacks.RunInfo, output callbacks.CallbackOutput) (json.RawMessage, *schema.TokenUsage) {
+	switch info.Component {
+	case components.ComponentOfChatModel:
+		if out := model.ConvCallbackOutput(output); out != nil {
+			return encodeValue(out.Message), modelUsage(out)
+		}
+	case components.ComponentOfTool:
+		if out := tool.ConvCallbackOutput(output); out != nil {
+			return encodeValue(out.Response), nil
+		}
+	case components.ComponentOfRetriever:
+		if out := retriever.ConvCallbackOutput(output); out != nil {
+			return encodeValue(out.Docs), nil
+		}
+	case components.ComponentOfEmbedding:
+		if out := embedding.ConvCallbackOutput(output); out != nil {
+			eo := embeddingOutput{Count: len(out.Embeddings)}
+			if len(out.Embeddings) > 0 {
+				eo.Dimensions = len(out.Embeddings[0])
+			}
+			var usage *schema.TokenUsage
+			if out.TokenUsage != nil {
+				usage = &schema.TokenUsage{
+					PromptTokens:     out.TokenUsage.PromptTokens,
+					CompletionTokens: out.TokenUsage.CompletionTokens,
+					TotalTokens:      out.TokenUsage.TotalTokens,
+				}
+			}
+			return encodeValue(eo), usage
+		}
+	case components.ComponentOfPrompt:
+		if out := prompt.ConvCallbackOutput(output); out != nil {
+			return encodeValue(out.Result), nil
+		}
+	}
+	return encodeValue(output), nil
+}
+
+// encodeStreamOutput 把流式输出的分片合并后再编码；ChatModel 分片合并为一条完整消息
+func encodeStreamOutput(info *callbacks.RunInfo, chunks []callbacks.CallbackOutput) (json.RawMessage, *schema.TokenUsage) {
+	if info.Component == components.ComponentOfChatModel {
+		var (
+			msgs  []*schema.Message
+			usage *schema.TokenUsage
+		)
+		for _, c := range chunks {
+			out := model.ConvCallbackOutput(c)
+			if out == nil {
+				continue
+			}
+			if out.Message != nil {
+				msgs = append(msgs, out.Message)
+			}
+			if u := modelUsage(out); u != nil {
+				usage = u
+			}
+		}
+		if len(msgs) > 0 {
+			if msg, err := schema.ConcatMessages(msgs); err == nil {
+				return encodeValue(msg), usage
+			}
+		}
+		return encodeValue(msgs), usage
+	}
+	return encodeChunks(chunks), nil
+}
+
+// encodeChunks 通用的流分片编码：全部是消息时合并为一条，否则按数组记录
+func encodeChunks[T any](chunks []T) json.RawMessage {
+	msgs := make([]*schema.Message, 0, len(chunks))
+	values := make([]any, 0, len(chunks))
+	for _, c := range chunks {
+		v := any(c)
+		values = append(values, v)
+		if m, ok := v.(*schema.Message); ok && m != nil {
+			msgs = append(msgs, m)
+		}
+	}
+	if len(msgs) > 0 && len(msgs) == len(values) {
+		if msg, err := schema.ConcatMessages(msgs); err == nil {
+			return encodeValue(msg)
+		}
+	}
+	if len(values) == 1 {
+		return encodeValue(values[0])
+	}
+	return encodeValue(values)
+}
+
+func modelUsage(out *model.CallbackOutput) *schema.TokenUsage {
+	if out.TokenUsage != nil {
+		return &schema.TokenUsage{
+			PromptTokens:       out.TokenUsage.PromptTokens,
+			PromptTokenDetails: schema.PromptTokenDetails{CachedTokens: out.TokenUsage.PromptTokenDetails.CachedTokens},
+			CompletionTokens:   out.TokenUsage.CompletionTokens,
+			TotalTokens:        out.TokenUsage.TotalTokens,
+		}
+	}
+	if out.Message != nil && out.Message.ResponseMeta != nil {
+		return out.Message.ResponseMeta.Usage
+	}
+	return nil
+}
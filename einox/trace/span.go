@@ -0,0 +1,75 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Span 一次组件执行的记录，多个 Span 通过 ParentID 组成一棵调用树
+type Span struct {
+	RunID     string             `json:"run_id"`
+	SpanID    string             `json:"span_id"`
+	ParentID  string             `json:"parent_id,omitempty"`
+	Name      string             `json:"name"`      // 节点名（compose.WithNodeName / 节点 key）
+	Type      string             `json:"type"`      // 组件实现类型，如 DeepSeek、Default
+	Component string             `json:"component"` // 组件类别，如 ChatModel、Tool、Graph
+	StartTime time.Time          `json:"start_time"`
+	EndTime   time.Time          `json:"end_time"`
+	LatencyMS int64              `json:"latency_ms"`
+	Stream    bool               `json:"stream,omitempty"` // 输入或输出是否为流
+	Input     json.RawMessage    `json:"input,omitempty"`
+	Output    json.RawMessage    `json:"output,omitempty"`
+	Usage     *schema.TokenUsage `json:"usage,omitempty"`
+	Error     string             `json:"error,omitempty"`
+}
+
+type runIDKey struct{}
+
+type spanKey struct{}
+
+// NewRunID 生成一个按时间排序的 run ID，例如 20240101T120000-1a2b3c4d
+func NewRunID() string {
+	return time.Now().Format("20060102T150405") + "-" + randomHex(4)
+}
+
+// WithRunID 预先指定本次执行的 run ID，便于调用方在执行结束后定位 trace 文件
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// RunIDFromContext 返回 ctx 中的 run ID（预先指定的或由根 Span 生成的）
+func RunIDFromContext(ctx context.Context) string {
+	if s, ok := ctx.Value(spanKey{}).(*activeSpan); ok {
+		return s.RunID
+	}
+	if id, ok := ctx.Value(runIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// encodeValue 把任意回调输入/输出转成 JSON；无法序列化的值（函数、channel 等）退化为字符串
+func encodeValue(v any) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprintf("%+v", v))
+	}
+	return b
+}
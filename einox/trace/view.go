@@ -0,0 +1,313 @@
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Node Span 树上的一个节点
+type Node struct {
+	*Span
+	Children []*Node
+}
+
+// Run 一次执行的完整记录
+type Run struct {
+	ID    string
+	Roots []*Node
+	Start time.Time
+	End   time.Time
+}
+
+// Duration 整次执行的耗时
+func (r *Run) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Walk 深度优先遍历，depth 从 0 开始
+func (r *Run) Walk(fn func(n *Node, depth int)) {
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, n := range nodes {
+			fn(n, depth)
+			walk(n.Children, depth+1)
+		}
+	}
+	walk(r.Roots, 0)
+}
+
+// ResolvePath 根据 run ID 找到 trace 文件；runID 为 latest 时取目录中最新的一次
+func ResolvePath(dir, runID string) (string, error) {
+	if runID == "latest" {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+		if err != nil {
+			return "", err
+		}
+		if len(matches) == 0 {
+			return "", fmt.Errorf("no trace found in %s", dir)
+		}
+		sort.Strings(matches)
+		return matches[len(matches)-1], nil
+	}
+	if strings.HasSuffix(runID, ".jsonl") {
+		return runID, nil
+	}
+	path := filepath.Join(dir, runID+".jsonl")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	// 允许只输入 run ID 的唯一前缀
+	matches, err := filepath.Glob(filepath.Join(dir, runID+"*.jsonl"))
+	if err != nil {
+		return "", err
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("trace %s not found in %s", runID, dir)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("run id prefix %q is ambiguous (%d matches)", runID, len(matches))
+	}
+}
+
+// LoadRun 读取 JSONL trace 文件并重建 Span 树
+func LoadRun(path string) (*Run, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var spans []*Span
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		s := &Span{}
+		if err := json.Unmarshal(line, s); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		spans = append(spans, s)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(spans) == 0 {
+		return nil, errors.New("empty trace")
+	}
+	return buildRun(spans), nil
+}
+
+func buildRun(spans []*Span) *Run {
+	nodes := make(map[string]*Node, len(spans))
+	for _, s := range spans {
+		nodes[s.SpanID] = &Node{Span: s}
+	}
+
+	run := &Run{ID: spans[0].RunID}
+	for _, s := range spans {
+		n := nodes[s.SpanID]
+		if parent, ok := nodes[s.ParentID]; ok && s.ParentID != "" {
+			parent.Children = append(parent.Children, n)
+		} else {
+			run.Roots = append(run.Roots, n)
+		}
+		if run.Start.IsZero() || s.StartTime.Before(run.Start) {
+			run.Start = s.StartTime
+		}
+		if s.EndTime.After(run.End) {
+			run.End = s.EndTime
+		}
+	}
+
+	byStart := func(ns []*Node) {
+		sort.SliceStable(ns, func(i, j int) bool { return ns[i].StartTime.Before(ns[j].StartTime) })
+	}
+	byStart(run.Roots)
+	for _, n := range nodes {
+		byStart(n.Children)
+	}
+	return run
+}
+
+// TextOptions 终端渲染选项
+type TextOptions struct {
+	// Expand 需要展开输入输出的节点名，如 tools、chat_recommend
+	Expand []string
+	// ExpandAll 展开所有节点
+	ExpandAll bool
+	// BarWidth 时间轴宽度，默认 30
+	BarWidth int
+}
+
+func (o *TextOptions) expanded(n *Node) bool {
+	if o.ExpandAll {
+		return true
+	}
+	for _, name := range o.Expand {
+		if name == n.Name || name == n.SpanID {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderText 把 Span 树渲染成带时间轴的缩进文本
+func RenderText(w io.Writer, run *Run, opts *TextOptions) error {
+	if opts == nil {
+		opts = &TextOptions{}
+	}
+	width := opts.BarWidth
+	if width <= 0 {
+		width = 30
+	}
+	total := run.Duration()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "run %s  start %s  total %s\n\n",
+		run.ID, run.Start.Format("2006-01-02 15:04:05"), formatDuration(total))
+
+	run.Walk(func(n *Node, depth int) {
+		offset := n.StartTime.Sub(run.Start)
+		indent := strings.Repeat("  ", depth)
+		label := displayName(n)
+		line := fmt.Sprintf("%s %s%-*s %9s  +%-9s",
+			timelineBar(offset, n.EndTime.Sub(n.StartTime), total, width),
+			indent, 36-len(indent), label, formatDuration(n.EndTime.Sub(n.StartTime)), formatDuration(offset))
+		if n.Usage != nil {
+			line += fmt.Sprintf("  tokens %d/%d", n.Usage.PromptTokens, n.Usage.CompletionTokens)
+		}
+		if n.Error != "" {
+			line += "  ERROR: " + n.Error
+		}
+		fmt.Fprintln(bw, strings.TrimRight(line, " "))
+
+		if opts.expanded(n) {
+			pad := strings.Repeat(" ", width+3) + indent + "  │ "
+			writeIndented(bw, pad, "input:\n"+FormatPayload(n.Input))
+			writeIndented(bw, pad, "output:\n"+FormatPayload(n.Output))
+		}
+	})
+	return bw.Flush()
+}
+
+// displayName 节点显示名：name (Component/Type)
+func displayName(n *Node) string {
+	name := n.Name
+	if name == "" {
+		name = n.Component
+	}
+	kind := n.Component
+	if n.Type != "" {
+		kind += "/" + n.Type
+	}
+	if kind == name || kind == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, kind)
+}
+
+func timelineBar(offset, dur, total time.Duration, width int) string {
+	if total <= 0 {
+		return "[" + strings.Repeat("█", width) + "]"
+	}
+	start := int(float64(offset) / float64(total) * float64(width))
+	length := int(float64(dur)/float64(total)*float64(width) + 0.5)
+	if length < 1 {
+		length = 1
+	}
+	if start >= width {
+		start = width - 1
+	}
+	if start+length > width {
+		length = width - start
+	}
+	return "[" + strings.Repeat(" ", start) + strings.Repeat("█", length) + strings.Repeat(" ", width-start-length) + "]"
+}
+
+func writeIndented(w io.Writer, prefix, text string) {
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		fmt.Fprintln(w, prefix+line)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return fmt.Sprintf("%dµs", d.Microseconds())
+	}
+}
+
+// FormatPayload 把 Span 的输入/输出格式化为可读文本
+// 消息（或消息列表）按 "[role] content" 展示，其余内容输出缩进 JSON
+func FormatPayload(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return "(empty)"
+	}
+
+	var mi modelInput
+	if err := json.Unmarshal(raw, &mi); err == nil && len(mi.Messages) > 0 {
+		text := formatMessages(mi.Messages)
+		if len(mi.Tools) > 0 {
+			text += "tools: " + strings.Join(mi.Tools, ", ") + "\n"
+		}
+		return text
+	}
+	var msgs []*schema.Message
+	if err := json.Unmarshal(raw, &msgs); err == nil && len(msgs) > 0 && msgs[0] != nil && msgs[0].Role != "" {
+		return formatMessages(msgs)
+	}
+	var msg schema.Message
+	if err := json.Unmarshal(raw, &msg); err == nil && msg.Role != "" {
+		return formatMessages([]*schema.Message{&msg})
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "  "); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+func formatMessages(msgs []*schema.Message) string {
+	var b strings.Builder
+	for _, m := range msgs {
+		if m == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "[%s] %s\n", m.Role, m.Content)
+		if m.ReasoningContent != "" {
+			fmt.Fprintf(&b, "  reasoning: %s\n", m.ReasoningContent)
+		}
+		for _, tc := range m.ToolCalls {
+			fmt.Fprintf(&b, "  tool_call %s(%s)\n", tc.Function.Name, tc.Function.Arguments)
+		}
+		if m.ToolCallID != "" {
+			fmt.Fprintf(&b, "  tool_call_id: %s\n", m.ToolCallID)
+		}
+	}
+	return b.String()
+}
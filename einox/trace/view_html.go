@@ -0,0 +1,114 @@
+package trace
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlRow 瀑布图中的一行
+type htmlRow struct {
+	Name     string
+	Kind     string
+	Depth    int
+	Left     float64 // 相对整次执行的起点，百分比
+	Width    float64 // 相对整次执行的耗时，百分比
+	Duration string
+	Offset   string
+	Usage    string
+	Error    string
+	Input    string
+	Output   string
+}
+
+var htmlTpl = template.Must(template.New("trace").Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="UTF-8">
+<title>trace {{.ID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", sans-serif; margin: 24px; color: #222; }
+h1 { font-size: 18px; margin-bottom: 4px; }
+.meta { color: #666; margin-bottom: 16px; font-size: 13px; }
+.row { border-bottom: 1px solid #eee; }
+.row summary { display: grid; grid-template-columns: 340px 1fr 90px; align-items: center; gap: 12px; padding: 4px 0; cursor: pointer; list-style: none; font-size: 13px; }
+.row summary::-webkit-details-marker { display: none; }
+.name { white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.kind { color: #888; }
+.track { position: relative; height: 14px; background: #f6f6f6; border-radius: 2px; }
+.bar { position: absolute; top: 0; height: 14px; min-width: 2px; background: #4c8bf5; border-radius: 2px; }
+.bar.error { background: #e5534b; }
+.dur { text-align: right; font-variant-numeric: tabular-nums; }
+.detail { display: grid; grid-template-columns: 1fr 1fr; gap: 12px; padding: 8px 0 12px; }
+.detail h3 { font-size: 12px; margin: 0 0 4px; color: #555; }
+.detail pre { margin: 0; padding: 8px; background: #fafafa; border: 1px solid #eee; font-size: 12px; white-space: pre-wrap; word-break: break-word; max-height: 480px; overflow: auto; }
+.info { font-size: 12px; color: #666; padding-top: 6px; }
+.err { color: #e5534b; }
+</style>
+</head>
+<body>
+<h1>trace {{.ID}}</h1>
+<div class="meta">start {{.Start}} · total {{.Total}} · {{len .Rows}} spans · 点击任意节点展开输入输出</div>
+{{range .Rows}}<details class="row">
+<summary>
+<span class="name" style="padding-left: {{.Depth}}em">{{.Name}} <span class="kind">{{.Kind}}</span></span>
+<span class="track"><span class="bar{{if .Error}} error{{end}}" style="left: {{printf "%.3f" .Left}}%; width: {{printf "%.3f" .Width}}%"></span></span>
+<span class="dur">{{.Duration}}</span>
+</summary>
+<div class="info">offset +{{.Offset}}{{if .Usage}} · tokens {{.Usage}}{{end}}{{if .Error}} · <span class="err">{{.Error}}</span>{{end}}</div>
+<div class="detail">
+<div><h3>input</h3><pre>{{.Input}}</pre></div>
+<div><h3>output</h3><pre>{{.Output}}</pre></div>
+</div>
+</details>
+{{end}}
+</body>
+</html>
+`))
+
+// RenderHTML 输出自包含的 HTML 瀑布图，每个节点可展开查看输入输出
+func RenderHTML(w io.Writer, run *Run) error {
+	total := run.Duration()
+	percent := func(d time.Duration) float64 {
+		if total <= 0 {
+			return 100
+		}
+		return float64(d) / float64(total) * 100
+	}
+
+	var rows []htmlRow
+	run.Walk(func(n *Node, depth int) {
+		dur := n.EndTime.Sub(n.StartTime)
+		offset := n.StartTime.Sub(run.Start)
+		row := htmlRow{
+			Name:     n.Name,
+			Kind:     n.Component,
+			Depth:    depth,
+			Left:     percent(offset),
+			Width:    percent(dur),
+			Duration: formatDuration(dur),
+			Offset:   formatDuration(offset),
+			Error:    n.Error,
+			Input:    FormatPayload(n.Input),
+			Output:   FormatPayload(n.Output),
+		}
+		if row.Name == "" {
+			row.Name = n.Component
+		}
+		if n.Type != "" {
+			row.Kind += "/" + n.Type
+		}
+		if n.Usage != nil {
+			row.Usage = fmt.Sprintf("%d/%d", n.Usage.PromptTokens, n.Usage.CompletionTokens)
+		}
+		rows = append(rows, row)
+	})
+
+	return htmlTpl.Execute(w, map[string]any{
+		"ID":    run.ID,
+		"Start": run.Start.Format("2006-01-02 15:04:05"),
+		"Total": formatDuration(total),
+		"Rows":  rows,
+	})
+}
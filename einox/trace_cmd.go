@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/NuyoahCh/einotelos/einox/trace"
+)
+
+// runTrace einox trace 子命令
+func runTrace(args []string) error {
+	if len(args) == 0 || args[0] != "show" {
+		return errors.New("usage: einox trace show <run-id|latest> [-dir traces] [-expand tools,chat_recommend] [-all] [-html out.html]")
+	}
+	return runTraceShow(args[1:])
+}
+
+// runTraceShow 在终端渲染 Span 树，或输出 HTML 瀑布图
+func runTraceShow(args []string) error {
+	fs := flag.NewFlagSet("trace show", flag.ExitOnError)
+	dir := fs.String("dir", "traces", "trace 文件目录")
+	expand := fs.String("expand", "", "展开输入输出的节点名，逗号分隔，如 tools,chat_recommend")
+	all := fs.Bool("all", false, "展开所有节点")
+	htmlOut := fs.String("html", "", "输出自包含的 HTML 瀑布图到指定文件")
+
+	// 允许 run-id 写在 flag 前后任意位置
+	var positional []string
+	for len(args) > 0 {
+		_ = fs.Parse(args)
+		args = fs.Args()
+		if len(args) > 0 {
+			positional = append(positional, args[0])
+			args = args[1:]
+		}
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: einox trace show <run-id|latest> [flags]")
+	}
+
+	path, err := trace.ResolvePath(*dir, positional[0])
+	if err != nil {
+		return err
+	}
+	run, err := trace.LoadRun(path)
+	if err != nil {
+		return err
+	}
+
+	if *htmlOut != "" {
+		f, err := os.Create(*htmlOut)
+		if err != nil {
+			return err
+		}
+		if err := trace.RenderHTML(f, run); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("HTML 已写入: %s\n", *htmlOut)
+		return nil
+	}
+
+	opts := &trace.TextOptions{ExpandAll: *all}
+	if *expand != "" {
+		opts.Expand = strings.Split(*expand, ",")
+	}
+	return trace.RenderText(os.Stdout, run, opts)
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/NuyoahCh/einotelos/einox/metrics"
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
 	"github.com/cloudwego/eino/schema"
 )
@@ -16,6 +17,13 @@ func main() {
 	// 1. 创建上下文
 	ctx := context.Background()
 
+	// 设置 EINOX_METRICS_ADDR（如 :9090）时在本进程暴露 /metrics，采集模型、工具调用指标
+	metricsSrv, err := metrics.ServeFromEnv()
+	if err != nil {
+		log.Fatalf("启动指标服务失败: %v", err)
+	}
+	defer metricsSrv.Close()
+
 	// 2. 创建 ChatModel 实例
 	chatModel, err := deepseek.NewChatModel(ctx, &deepseek.ChatModelConfig{
 		// 提供火山 ARK 的 APIKey 模型名称的可选项
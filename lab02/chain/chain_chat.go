@@ -5,6 +5,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/NuyoahCh/einotelos/einox/guard"
+	"github.com/NuyoahCh/einotelos/einox/metrics"
+	"github.com/NuyoahCh/einotelos/einox/trace"
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/components/tool"
@@ -33,6 +36,13 @@ type playerInfoResponse struct {
 func main() {
 	ctx := context.Background()
 
+	// 设置 EINOX_METRICS_ADDR（如 :9090）时在本进程暴露 /metrics，采集模型、工具调用指标
+	metricsSrv, err := metrics.ServeFromEnv()
+	if err != nil {
+		log.Fatalf("启动指标服务失败: %v", err)
+	}
+	defer metricsSrv.Close()
+
 	// 1) 篮球主题：ChatTemplate
 	systemTpl := `你是一名篮球教练与比赛分析师。你需要结合用户的基本信息与训练习惯，
 使用 player_info 工具补全信息，然后给出适合他的训练计划/位置建议/一套简单战术建议。
@@ -44,6 +54,13 @@ func main() {
 		schema.UserMessage("{user_query}"),
 	)
 
+	// 用户输入不可信：命中注入特征时加分隔符隔离，分数过高直接拒绝；守卫结论会出现在 trace 中
+	inputGuard, err := guard.New(&guard.Config{})
+	if err != nil {
+		panic(err)
+	}
+	guardedTpl := inputGuard.Template(chatTpl, "user_query")
+
 	// 2) 推荐模板（工具回来的信息 + 固定“篮球知识库/规则”）
 	recommendTpl := `
 你是一名篮球教练与比赛分析师。请结合工具返回的用户信息，为用户输出建议，要求具体、可执行。
@@ -138,7 +155,7 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	
+
 	// 7) lambda：从 toolsNode 输出中提取“工具返回内容”，转成普通 user 文本（避免 role=tool）
 	toolToTextOps := func(
 		ctx context.Context,
@@ -190,7 +207,7 @@ func main() {
 	// 9) Chain 编排：template -> chat -> tools -> lambda -> lambdaPrompt -> chat
 	chain := compose.NewChain[map[string]any, *schema.Message]()
 	chain.
-		AppendChatTemplate(chatTpl).
+		AppendChatTemplate(guardedTpl).
 		AppendChatModel(chatModel).
 		AppendToolsNode(toolsNode).
 		AppendLambda(lambdaToolToText).
@@ -203,10 +220,18 @@ func main() {
 		panic(err)
 	}
 
+	// 挂上 trace handler：每个节点的输入输出、耗时、token 用量写入 traces/{run_id}.jsonl
+	tracer, err := trace.NewHandler(&trace.Config{Dir: "traces"})
+	if err != nil {
+		panic(err)
+	}
+	runID := trace.NewRunID()
+	ctx = trace.WithRunID(ctx, runID)
+
 	output, err := runnable.Invoke(ctx, map[string]any{
 		"histories":  []*schema.Message{},
 		"user_query": "我叫 morning, 邮箱是 lumworn@gmail.com。我的目标是提升实战表现，帮我制定训练计划并推荐适合的位置和打法。",
-	})
+	}, compose.WithCallbacks(tracer))
 	if err != nil {
 		panic(err)
 	}
@@ -227,4 +252,6 @@ func main() {
 		println("  输出 Token:", int(output.ResponseMeta.Usage.CompletionTokens))
 		println("  总计 Token:", int(output.ResponseMeta.Usage.TotalTokens))
 	}
+
+	println("\ntrace 文件:", tracer.Path(runID))
 }
@@ -7,6 +7,9 @@ import (
 	"log"
 	"os"
 
+	"github.com/NuyoahCh/einotelos/einox/guard"
+	"github.com/NuyoahCh/einotelos/einox/metrics"
+	"github.com/NuyoahCh/einotelos/einox/trace"
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/components/tool"
@@ -35,6 +38,13 @@ type playerInfoResponse struct {
 func main() {
 	ctx := context.Background()
 
+	// 设置 EINOX_METRICS_ADDR（如 :9090）时在本进程暴露 /metrics，采集模型、工具调用指标
+	metricsSrv, err := metrics.ServeFromEnv()
+	if err != nil {
+		log.Fatalf("启动指标服务失败: %v", err)
+	}
+	defer metricsSrv.Close()
+
 	// 创建 Workflow 编排
 	wf := compose.NewWorkflow[map[string]any, *schema.Message]()
 
@@ -49,6 +59,13 @@ func main() {
 		schema.UserMessage("{user_query}"),
 	)
 
+	// 用户输入不可信：命中注入特征时加分隔符隔离，分数过高直接拒绝；守卫结论会出现在 trace 中
+	inputGuard, err := guard.New(&guard.Config{})
+	if err != nil {
+		panic(err)
+	}
+	guardedTpl := inputGuard.Template(chatTpl, "user_query")
+
 	// 2) 推荐模板
 	recommendTpl := `
 你是一名篮球教练与比赛分析师。请结合“工具返回的用户信息”，为用户输出建议，要求具体、可执行。
@@ -186,7 +203,7 @@ func main() {
 	lambdaPrompt := compose.TransformableLambda[*schema.Message, []*schema.Message](promptTransformOps)
 
 	// 9) 添加节点到 Workflow
-	wf.AddChatTemplateNode("prompt", chatTpl).AddInput(compose.START)
+	wf.AddChatTemplateNode("prompt", guardedTpl).AddInput(compose.START)
 	wf.AddChatModelNode("chat", chatModel).AddInput("prompt")
 	wf.AddToolsNode("tools", toolsNode).AddInput("chat")
 
@@ -203,10 +220,18 @@ func main() {
 		panic(err)
 	}
 
+	// 挂上 trace handler：每个节点的输入输出、耗时、token 用量写入 traces/{run_id}.jsonl
+	tracer, err := trace.NewHandler(&trace.Config{Dir: "traces"})
+	if err != nil {
+		panic(err)
+	}
+	runID := trace.NewRunID()
+	ctx = trace.WithRunID(ctx, runID)
+
 	output, err := runnable.Invoke(ctx, map[string]any{
 		"histories":  []*schema.Message{},
 		"user_query": "我叫 morning, 邮箱是 lumworn@gmail.com。我的目标是提升实战表现，帮我制定训练计划并推荐适合的位置和打法。",
-	})
+	}, compose.WithCallbacks(tracer))
 	if err != nil {
 		panic(err)
 	}
@@ -219,4 +244,6 @@ func main() {
 	if output.Content != "" {
 		println(output.Content)
 	}
+
+	println("\ntrace 文件:", tracer.Path(runID))
 }
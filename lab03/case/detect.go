@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// latinStopwords 各拉丁语系语言的高频词，用于区分英/法/德/西/葡/意
+var latinStopwords = map[string][]string{
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "you", "for", "with", "this", "how", "what", "be", "was", "on"},
+	"fr": {"le", "la", "les", "et", "est", "sont", "des", "un", "une", "du", "que", "qui", "pas", "pour", "dans", "ce", "avec", "je"},
+	"de": {"der", "die", "das", "und", "ist", "sind", "nicht", "ein", "eine", "mit", "zu", "den", "von", "auf", "ich", "für", "es"},
+	"es": {"el", "los", "las", "es", "son", "y", "de", "que", "en", "un", "una", "por", "con", "para", "no", "lo", "está"},
+	"pt": {"o", "os", "as", "é", "são", "e", "de", "que", "em", "um", "uma", "não", "para", "com", "do", "da", "está"},
+	"it": {"il", "lo", "gli", "le", "è", "sono", "e", "di", "che", "un", "una", "non", "per", "con", "del", "della"},
+}
+
+// DetectLanguage 按文字系统与高频词推断文本语言，返回语言代码（zh、en、ja ...）
+// 无法判断（文本过短、没有明显特征）时返回空字符串
+func DetectLanguage(text string) string {
+	var han, kana, hangul, cyrillic, latin int
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	// 拉丁字母约 4 个算一个"字"，避免中文里夹杂的产品名把结果带偏
+	cjk := han + kana + hangul
+	switch {
+	case cjk == 0 && cyrillic == 0 && latin == 0:
+		return ""
+	case cjk*4 >= latin && cjk >= cyrillic:
+		switch {
+		case hangul > 0 && hangul >= han:
+			return "ko"
+		case kana > 0:
+			return "ja"
+		default:
+			return "zh"
+		}
+	case cyrillic > latin:
+		return "ru"
+	}
+	return detectLatin(text)
+}
+
+// detectLatin 统计各语言高频词命中数，命中最多且领先的语言胜出
+func detectLatin(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	scores := make(map[string]int, len(latinStopwords))
+	for lang, stopwords := range latinStopwords {
+		set := make(map[string]bool, len(stopwords))
+		for _, w := range stopwords {
+			set[w] = true
+		}
+		for _, w := range words {
+			if set[w] {
+				scores[lang]++
+			}
+		}
+	}
+
+	best, bestScore, tie := "", 0, false
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = lang, score, false
+		case score == bestScore && score > 0:
+			tie = true
+		}
+	}
+	if bestScore == 0 || tie {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	markdown "github.com/cloudwego/eino-ext/components/document/transformer/splitter/markdown"
+	recursive "github.com/cloudwego/eino-ext/components/document/transformer/splitter/recursive"
+	"github.com/cloudwego/eino/schema"
+)
+
+// docPart 文档切分后的一段：要么原样保留（代码、标记、空白），要么交给模型翻译
+// 所有 part 按顺序拼接即还原出原文，翻译后用译文替换 translate 段即可保持结构
+type docPart struct {
+	text      string
+	translate bool
+	// protected 本段中的占位符 -> 原文（代码、链接地址、内联标签等）
+	protected map[string]string
+}
+
+// placeholder 受保护内容的占位符，形如 ⟦0⟧；选用少见字符避免与正文冲突
+func placeholder(i int) string {
+	return fmt.Sprintf("⟦%d⟧", i)
+}
+
+var placeholderRe = regexp.MustCompile(`⟦\d+⟧`)
+
+// docBuilder 逐段收集 docPart
+type docBuilder struct {
+	parts     []*docPart
+	cur       strings.Builder
+	protected map[string]string
+	next      int
+}
+
+// keep 原样保留 s，同时结束当前正在累积的翻译段
+func (b *docBuilder) keep(s string) {
+	b.flush()
+	b.appendKeep(s)
+}
+
+func (b *docBuilder) appendKeep(s string) {
+	if s == "" {
+		return
+	}
+	if n := len(b.parts); n > 0 && !b.parts[n-1].translate {
+		b.parts[n-1].text += s
+		return
+	}
+	b.parts = append(b.parts, &docPart{text: s})
+}
+
+// text 追加待翻译文本（可包含 protect 生成的占位符）
+func (b *docBuilder) text(s string) {
+	b.cur.WriteString(s)
+}
+
+// protect 登记一段受保护内容，返回占位符
+func (b *docBuilder) protect(s string) string {
+	if b.protected == nil {
+		b.protected = make(map[string]string)
+	}
+	ph := placeholder(b.next)
+	b.next++
+	b.protected[ph] = s
+	return ph
+}
+
+// flush 结束当前翻译段；首尾空白归入保留段，没有文字的段（只有占位符、标点）不翻译
+func (b *docBuilder) flush() {
+	s := b.cur.String()
+	b.cur.Reset()
+	protected := b.protected
+	b.protected = nil
+	if s == "" {
+		return
+	}
+
+	body := strings.TrimSpace(s)
+	lead := s[:strings.Index(s, body)]
+	trail := s[len(lead)+len(body):]
+	if body == "" || !hasWords(body) {
+		b.appendKeep(restore(s, protected))
+		return
+	}
+	b.appendKeep(lead)
+	b.parts = append(b.parts, &docPart{text: body, translate: true, protected: protected})
+	b.appendKeep(trail)
+}
+
+func (b *docBuilder) done() []*docPart {
+	b.flush()
+	return b.parts
+}
+
+// hasWords 去掉占位符后是否还有需要翻译的文字
+func hasWords(s string) bool {
+	for _, r := range placeholderRe.ReplaceAllString(s, "") {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// restore 把占位符还原为原文
+func restore(s string, protected map[string]string) string {
+	if len(protected) == 0 {
+		return s
+	}
+	return placeholderRe.ReplaceAllStringFunc(s, func(ph string) string {
+		if orig, ok := protected[ph]; ok {
+			return orig
+		}
+		return ph
+	})
+}
+
+// ---------- Markdown ----------
+
+var (
+	// mdInlineRe Markdown 行内需要保护的内容：行内代码、链接/图片地址、自动链接、内联 HTML、裸 URL
+	mdInlineRe = regexp.MustCompile("``[^`]+``|`[^`\n]+`|\\]\\([^)\\s]*(?:\\s+\"[^\"]*\")?\\)|<https?://[^>]+>|</?[a-zA-Z][^>\n]*>|https?://[^\\s)>\\]]+")
+	// mdListRe 列表项
+	mdListRe = regexp.MustCompile(`^[ \t]{0,3}(?:[-*+]|\d+[.)])[ \t]+`)
+	// mdPrefixRe 行首的结构标记：标题、列表、引用
+	mdPrefixRe = regexp.MustCompile(`^[ \t]*(?:(?:#{1,6}|[-*+]|\d+[.)]|>)[ \t]+)+(?:\[[ xX]\][ \t]+)?`)
+	// mdKeepLineRe 整行原样保留：表格分隔行、分隔线、Setext 标题下划线、链接引用定义、HTML 注释
+	mdKeepLineRe = regexp.MustCompile(`^[ \t]*(?:\|?[ \t]*:?-{3,}:?[ \t]*(?:\|[ \t]*:?-{3,}:?[ \t]*)*\|?|(?:[-*_][ \t]*){3,}|=+|\[[^\]]+\]:[ \t]*\S.*|<!--.*-->)[ \t]*\r?\n?$`)
+)
+
+// markdownSections 用 lab05 中的 Markdown 标题分割器切出小节，返回每个小节在原文中的起始行号
+// 分割器会去掉行首尾空白和空行，这里按顺序用每个小节的标题行回到原文中定位
+func markdownSections(ctx context.Context, lines []string) (map[int]bool, error) {
+	splitter, err := markdown.NewHeaderSplitter(ctx, &markdown.HeaderConfig{
+		Headers: map[string]string{
+			"#": "h1", "##": "h2", "###": "h3", "####": "h4", "#####": "h5", "######": "h6",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	docs, err := splitter.Transform(ctx, []*schema.Document{{Content: strings.Join(lines, "")}})
+	if err != nil {
+		return nil, err
+	}
+
+	starts := make(map[int]bool, len(docs))
+	next := 0
+	for _, doc := range docs {
+		heading, _, _ := strings.Cut(doc.Content, "\n")
+		if !strings.HasPrefix(heading, "#") {
+			continue
+		}
+		for i := next; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == heading {
+				starts[i], next = true, i+1
+				break
+			}
+		}
+	}
+	return starts, nil
+}
+
+// isIndentedCode 缩进 4 个空格或 1 个 Tab 的非空行
+func isIndentedCode(line string) bool {
+	return (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != ""
+}
+
+// segmentMarkdown 先按标题分割器切出的小节划分边界，再在小节内按行扫描：
+// 围栏与缩进代码块、front matter 原样保留，标题/列表项/表格行单独成段，普通段落按行合并
+func segmentMarkdown(ctx context.Context, content string) ([]*docPart, error) {
+	lines := strings.SplitAfter(content, "\n")
+	sections, err := markdownSections(ctx, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &docBuilder{}
+	mask := func(s string) string {
+		return mdInlineRe.ReplaceAllStringFunc(s, b.protect)
+	}
+
+	fence, frontMatter := "", false
+	// indented 正在缩进代码块中；inList 列表内缩进的行是列表项的续行，不是代码
+	indented, inList, prevBlank := false, false, true
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		blank := trimmed == ""
+		if sections[i] && fence == "" && !frontMatter {
+			// 段落不跨小节
+			b.keep("")
+		}
+		if indented && !blank && !isIndentedCode(line) {
+			indented = false
+		}
+		if !blank && !isIndentedCode(line) {
+			inList = mdListRe.MatchString(line)
+		}
+		switch {
+		case i == 0 && trimmed == "---":
+			frontMatter = true
+			b.keep(line)
+		case frontMatter:
+			b.keep(line)
+			if trimmed == "---" || trimmed == "..." {
+				frontMatter = false
+			}
+		case fence != "":
+			b.keep(line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```"), strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			b.keep(line)
+		case indented, prevBlank && !inList && isIndentedCode(line):
+			// 缩进代码块不能打断段落，只能从空行之后开始
+			indented = true
+			b.keep(line)
+		case trimmed == "", mdKeepLineRe.MatchString(line):
+			b.keep(line)
+		case strings.HasPrefix(trimmed, "|"):
+			// 表格行单独成段，竖线交给模型保留
+			b.keep("")
+			b.text(mask(line))
+			b.flush()
+		default:
+			if prefix := mdPrefixRe.FindString(line); prefix != "" {
+				b.keep(prefix)
+				b.text(mask(line[len(prefix):]))
+				b.flush()
+				continue
+			}
+			b.text(mask(line))
+		}
+		prevBlank = blank
+	}
+	return b.done(), nil
+}
+
+// ---------- HTML ----------
+
+var (
+	htmlTagNameRe = regexp.MustCompile(`^</?([a-zA-Z][a-zA-Z0-9-]*)`)
+	htmlEntityRe  = regexp.MustCompile(`&(?:[a-zA-Z]+|#[0-9]+|#[xX][0-9a-fA-F]+);`)
+)
+
+// htmlRawTags 内容整体保留、不翻译的元素
+var htmlRawTags = map[string]bool{"script": true, "style": true, "pre": true, "textarea": true, "code": true}
+
+// htmlInlineTags 行内元素：作为占位符留在句子里，不打断翻译段
+var htmlInlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true, "data": true,
+	"dfn": true, "em": true, "i": true, "kbd": true, "mark": true, "q": true, "s": true, "samp": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true, "time": true, "u": true, "var": true,
+	"img": true, "wbr": true,
+}
+
+// segmentHTML 按标签扫描 HTML：块级标签切分翻译段，行内标签与实体转成占位符，script/style/pre/code 整体保留
+func segmentHTML(content string) []*docPart {
+	b := &docBuilder{}
+	lower := strings.ToLower(content)
+	for i := 0; i < len(content); {
+		switch {
+		case strings.HasPrefix(content[i:], "<!--"):
+			end := strings.Index(content[i:], "-->")
+			if end < 0 {
+				end = len(content) - i - 3
+			}
+			b.keep(content[i : i+end+3])
+			i += end + 3
+
+		case content[i] == '<' && htmlTagNameRe.MatchString(content[i:]) || strings.HasPrefix(content[i:], "<!"):
+			end := strings.IndexByte(content[i:], '>')
+			if end < 0 {
+				b.text(content[i:])
+				i = len(content)
+				continue
+			}
+			tag := content[i : i+end+1]
+			name, closing := "", strings.HasPrefix(tag, "</")
+			if m := htmlTagNameRe.FindStringSubmatch(tag); m != nil {
+				name = strings.ToLower(m[1])
+			}
+			i += end + 1
+
+			// 原样保留的元素：连同内容一起吃掉
+			if htmlRawTags[name] && !closing && !strings.HasSuffix(tag, "/>") {
+				if close := strings.Index(lower[i:], "</"+name); close >= 0 {
+					closeEnd := strings.IndexByte(content[i+close:], '>')
+					if closeEnd >= 0 {
+						tag += content[i : i+close+closeEnd+1]
+						i += close + closeEnd + 1
+					}
+				}
+			}
+			if htmlInlineTags[name] {
+				b.text(b.protect(tag))
+			} else {
+				b.keep(tag)
+			}
+
+		default:
+			next := strings.IndexByte(content[i:], '<')
+			if next < 0 {
+				next = len(content) - i
+			} else if next == 0 {
+				// 不是标签的 "<"，按普通文本处理
+				next = 1
+			}
+			b.text(htmlEntityRe.ReplaceAllStringFunc(content[i:i+next], b.protect))
+			i += next
+		}
+	}
+	return b.done()
+}
+
+// ---------- 纯文本 ----------
+
+var urlRe = regexp.MustCompile(`https?://[^\s)>\]]+`)
+
+// segmentText 纯文本按空行分段，URL 作为占位符保护
+func segmentText(content string) []*docPart {
+	b := &docBuilder{}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.keep(line)
+			continue
+		}
+		b.text(urlRe.ReplaceAllStringFunc(line, b.protect))
+	}
+	return b.done()
+}
+
+// ---------- 超长段落 ----------
+
+// splitLongParts 用 lab05 中的递归分割器把超过 chunkSize 的翻译段切成多段
+// 分割器会去掉块首尾空白，这里按顺序回到原文中定位每一块，块之间的空白作为保留段，保证拼接后与原文一致
+func splitLongParts(ctx context.Context, parts []*docPart, chunkSize int) ([]*docPart, error) {
+	splitter, err := recursive.NewSplitter(ctx, &recursive.Config{
+		ChunkSize:  chunkSize,
+		Separators: []string{"\n", "。", "！", "？", "；", ". ", "! ", "? ", "; "},
+		LenFunc:    utf8.RuneCountInString,
+		KeepType:   recursive.KeepTypeEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*docPart
+	for _, p := range parts {
+		if !p.translate || utf8.RuneCountInString(p.text) <= chunkSize {
+			out = append(out, p)
+			continue
+		}
+		chunks, err := splitter.Transform(ctx, []*schema.Document{{Content: p.text}})
+		if err != nil {
+			return nil, err
+		}
+
+		var pieces []*docPart
+		rest := p.text
+		for _, c := range chunks {
+			idx := strings.Index(rest, c.Content)
+			if idx < 0 {
+				pieces = nil
+				break
+			}
+			if idx > 0 {
+				pieces = append(pieces, &docPart{text: rest[:idx]})
+			}
+			pieces = append(pieces, &docPart{text: c.Content, translate: hasWords(c.Content), protected: p.protected})
+			rest = rest[idx+len(c.Content):]
+		}
+		if pieces == nil {
+			// 定位失败时整段翻译，宁可超长也不打乱结构
+			out = append(out, p)
+			continue
+		}
+		if rest != "" {
+			pieces = append(pieces, &docPart{text: rest})
+		}
+		for _, piece := range pieces {
+			if !piece.translate {
+				piece.text = restore(piece.text, p.protected)
+				piece.protected = nil
+			}
+		}
+		out = append(out, pieces...)
+	}
+	return out, nil
+}
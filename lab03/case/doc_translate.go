@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DocFormat 文档格式
+type DocFormat string
+
+const (
+	DocText     DocFormat = "text"
+	DocMarkdown DocFormat = "markdown"
+	DocHTML     DocFormat = "html"
+)
+
+// FormatFromPath 根据扩展名推断文档格式，未知扩展名按纯文本处理
+func FormatFromPath(path string) DocFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return DocMarkdown
+	case ".html", ".htm":
+		return DocHTML
+	default:
+		return DocText
+	}
+}
+
+// DocumentOptions 文档翻译选项
+type DocumentOptions struct {
+	// Format 文档格式，默认纯文本
+	Format DocFormat
+	// ChunkSize 单段最大字符数，超过时用递归分割器切开，默认 1500
+	ChunkSize int
+	// Concurrency 并发翻译的段数，默认 4
+	Concurrency int
+	// Review 非 nil 时对模型译出的段落做回译质量评估，结果写入 DocumentResult.Review；
+	// 被标记（含评估失败）的段落不会写入翻译记忆，评估失败不影响译文的返回
+	Review *QualityOptions
+}
+
+// DocumentResult 文档翻译结果
+type DocumentResult struct {
+	Content string
+	// Segments 实际送去翻译的段数
+	Segments int
+	// Untranslated 译文丢失了占位符（代码、链接等）、因此保留原文的段落
+	Untranslated []string
+	// Violations 重新提示后仍未遵守术语表的术语
+	Violations []TermViolation
+	// MemoryHits 直接取自翻译记忆的段数
+	MemoryHits int
+	// Skipped 已是目标语言、原样保留的段数
+	Skipped int
+	// Review 质量评估报告，仅在 DocumentOptions.Review 非 nil 时生成
+	Review *QualityReport
+}
+
+// TranslateDocument 翻译 Markdown / HTML / 纯文本文档，保持原有结构
+// 代码块、链接地址、标签等受保护内容不送给模型（或以占位符形式出现），各段并发翻译后按原顺序拼回
+func (t *Translator) TranslateDocument(ctx context.Context, content, targetLang string, opts *DocumentOptions) (*DocumentResult, error) {
+	targetLang = strings.TrimSpace(targetLang)
+	if targetLang == "" {
+		return nil, errors.New("empty target language")
+	}
+	if opts == nil {
+		opts = &DocumentOptions{}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = 1500
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var (
+		parts []*docPart
+		err   error
+	)
+	switch opts.Format {
+	case DocMarkdown:
+		if parts, err = segmentMarkdown(ctx, content); err != nil {
+			return nil, err
+		}
+	case DocHTML:
+		parts = segmentHTML(content)
+	case DocText, "":
+		parts = segmentText(content)
+	default:
+		return nil, fmt.Errorf("unsupported document format: %s", opts.Format)
+	}
+	if parts, err = splitLongParts(ctx, parts, chunkSize); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := &DocumentResult{}
+	translated := make([]string, len(parts))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+		// reviewed 待评估的段落下标（按文档顺序排序后送评）
+		reviewed []int
+		sources  = make(map[int]string)
+	)
+	for i, p := range parts {
+		if !p.translate {
+			translated[i] = p.text
+			continue
+		}
+		result.Segments++
+
+		wg.Add(1)
+		go func(i int, p *docPart) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			src := restore(p.text, p.protected)
+			if lang := DetectLanguage(src); lang != "" && lang == normalizeLang(targetLang) {
+				mu.Lock()
+				result.Skipped++
+				translated[i] = src
+				mu.Unlock()
+				return
+			}
+			if target, hit := t.memory.Lookup(src, targetLang); hit {
+				mu.Lock()
+				result.MemoryHits++
+				translated[i] = target
+				mu.Unlock()
+				return
+			}
+
+			out, ok, violations, err := t.translateSegment(ctx, p, targetLang, opts.Format)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			if !ok {
+				result.Untranslated = append(result.Untranslated, restore(p.text, p.protected))
+			}
+			result.Violations = append(result.Violations, violations...)
+			if ok && opts.Review != nil {
+				reviewed = append(reviewed, i)
+				sources[i] = src
+			} else if ok && len(violations) == 0 {
+				t.memory.Add(src, targetLang, out)
+			}
+			translated[i] = out
+		}(i, p)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result.Content = strings.Join(translated, "")
+
+	if opts.Review != nil {
+		sort.Ints(reviewed)
+		pairs := make([]SegmentPair, len(reviewed))
+		for n, i := range reviewed {
+			pairs[n] = SegmentPair{Source: sources[i], Translation: translated[i]}
+		}
+		// 单段评估失败只会标记为需要人工审校，Review 仅在 ctx 取消时返回错误
+		report, err := t.Review(ctx, pairs, targetLang, opts.Review)
+		if err != nil {
+			return nil, err
+		}
+		result.Review = report
+		// 通过评估且遵守术语表的段落作为认可的译文写入翻译记忆
+		for _, s := range report.Segments {
+			if !s.Flagged && len(checkTerms(s.Translation, t.glossary.Match(s.Source))) == 0 {
+				t.memory.Approve(s.Source, targetLang, s.Translation)
+			}
+		}
+	}
+	return result, nil
+}
+
+// translateSegment 翻译一段并还原占位符；占位符丢失时重试一次，仍失败则保留原文（ok 为 false）
+func (t *Translator) translateSegment(ctx context.Context, p *docPart, targetLang string, format DocFormat) (string, bool, []TermViolation, error) {
+	markup := "原有换行与缩进"
+	switch format {
+	case DocMarkdown:
+		markup = "原有换行、缩进与 Markdown 标记（如 **、_、[]、|）"
+	case DocHTML:
+		markup = "原有换行与空白"
+	}
+	system := fmt.Sprintf(
+		"你是一个专业翻译引擎。将用户输入翻译成%s。"+
+			"要求：只输出译文，不要解释；保留%s；"+
+			"形如 ⟦0⟧ 的占位符代表代码、链接、标签等受保护内容，必须原样保留在译文中对应的位置，不得翻译、删除或新增；"+
+			"不要添加引号；不要输出多余内容。",
+		targetLang, markup,
+	) + referencePrompt(t.memory.Fuzzy(restore(p.text, p.protected), targetLang))
+
+	for attempt := 0; attempt < 2; attempt++ {
+		out, violations, err := t.generateWithTerms(ctx, system, p.text)
+		if err != nil {
+			return "", false, nil, err
+		}
+		if placeholdersKept(p.text, out) {
+			return restore(out, p.protected), true, violations, nil
+		}
+	}
+	return restore(p.text, p.protected), false, nil, nil
+}
+
+// placeholdersKept 译文中的占位符与原文完全一致（同样的集合、各出现一次）
+func placeholdersKept(src, dst string) bool {
+	want := placeholderRe.FindAllString(src, -1)
+	got := placeholderRe.FindAllString(dst, -1)
+	if len(want) != len(got) {
+		return false
+	}
+	seen := make(map[string]int, len(want))
+	for _, ph := range want {
+		seen[ph]++
+	}
+	for _, ph := range got {
+		seen[ph]--
+		if seen[ph] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TranslateFile 翻译文件并写入 dst，格式未指定时按扩展名推断
+func (t *Translator) TranslateFile(ctx context.Context, src, dst, targetLang string, opts *DocumentOptions) (*DocumentResult, error) {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	o := DocumentOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Format == "" {
+		o.Format = FormatFromPath(src)
+	}
+
+	res, err := t.TranslateDocument(ctx, string(b), targetLang, &o)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(dst, []byte(res.Content), 0o644); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// main01 文档翻译示例
+func main01() {
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey:   os.Getenv("DEEPSEEK_API_KEY"),
+		Glossary: NewGlossary(nil, "Eino", "Chain", "Graph"),
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	markdown := "# Eino 快速开始\n\n" +
+		"Eino 是字节跳动开源的 **LLM 应用开发框架**，详见 [官方文档](https://www.cloudwego.io/zh/docs/eino/)。\n\n" +
+		"## 安装\n\n" +
+		"```bash\ngo get github.com/cloudwego/eino@latest\n```\n\n" +
+		"- 使用 `compose.NewChain` 编排组件\n" +
+		"- 通过 callbacks 观测每一次调用\n"
+
+	res, err := translator.TranslateDocument(context.Background(), markdown, "English", &DocumentOptions{Format: DocMarkdown})
+	if err != nil {
+		log.Fatalf("翻译失败: %v", err)
+	}
+	fmt.Printf("共翻译 %d 段\n\n%s\n", res.Segments, res.Content)
+	for _, seg := range res.Untranslated {
+		fmt.Printf("未翻译（占位符丢失）: %s\n", seg)
+	}
+	for _, v := range res.Violations {
+		fmt.Printf("术语未遵守: %s\n", v)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GlossaryEntry 一条术语：原文 -> 必须使用的译文；Target 为空表示不翻译，保留原文
+type GlossaryEntry struct {
+	Source string
+	Target string
+
+	// match 在原文中识别术语，expect 在译文中检查规定的写法，两者使用同样的大小写规则
+	match, expect *regexp.Regexp
+}
+
+// Expected 译文中必须出现的写法
+func (e GlossaryEntry) Expected() string {
+	if e.Target == "" {
+		return e.Source
+	}
+	return e.Target
+}
+
+// Glossary 术语表，一份术语表对应一个目标语言
+// 翻译时只把原文中出现的条目注入提示词，译文返回后逐条检查
+type Glossary struct {
+	entries []GlossaryEntry
+}
+
+// NewGlossary 创建术语表，doNotTranslate 为保留原文的术语（如产品名 Eino、Chain、Graph），按大小写精确匹配
+func NewGlossary(entries []GlossaryEntry, doNotTranslate ...string) *Glossary {
+	g := &Glossary{}
+	for _, e := range entries {
+		g.add(e)
+	}
+	for _, term := range doNotTranslate {
+		g.add(GlossaryEntry{Source: term})
+	}
+	return g
+}
+
+func (g *Glossary) add(e GlossaryEntry) {
+	e.Source = strings.TrimSpace(e.Source)
+	e.Target = strings.TrimSpace(e.Target)
+	if e.Source == "" {
+		return
+	}
+	e.match = termRegexp(e.Source, e.foldCase())
+	e.expect = termRegexp(e.Expected(), e.foldCase())
+	g.entries = append(g.entries, e)
+}
+
+// foldCase 需要翻译的术语忽略大小写（句首的 Framework 也算 framework）；
+// 保留原文的专有名词区分大小写，避免普通单词 chain、graph 命中 Chain、Graph
+func (e GlossaryEntry) foldCase() bool {
+	return e.Target != ""
+}
+
+// termRegexp 英文术语按整词匹配，避免 Chain 命中 blockchain
+func termRegexp(term string, foldCase bool) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(term)
+	if isWordByte(term[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(term[len(term)-1]) {
+		pattern += `\b`
+	}
+	if foldCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// LoadGlossary 从 CSV 文件加载术语表，每行 "原文,译文"，译文留空表示不翻译；# 开头的行为注释
+func LoadGlossary(path string) (*Glossary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	var entries []GlossaryEntry
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("load glossary %s: %w", path, err)
+		}
+		e := GlossaryEntry{Source: rec[0]}
+		if len(rec) > 1 {
+			e.Target = rec[1]
+		}
+		entries = append(entries, e)
+	}
+	return NewGlossary(entries), nil
+}
+
+// Match 返回原文中出现的术语
+func (g *Glossary) Match(text string) []GlossaryEntry {
+	if g == nil {
+		return nil
+	}
+	var out []GlossaryEntry
+	for _, e := range g.entries {
+		if e.match.MatchString(text) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// glossaryPrompt 注入系统提示词的术语要求
+func glossaryPrompt(entries []GlossaryEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n术语表（必须严格遵守）：")
+	for _, e := range entries {
+		if e.Target == "" {
+			fmt.Fprintf(&b, "\n- %s：专有名词，保持原文，不要翻译", e.Source)
+		} else {
+			fmt.Fprintf(&b, "\n- %s：译为 %s", e.Source, e.Target)
+		}
+	}
+	return b.String()
+}
+
+// TermViolation 译文没有按术语表处理的术语
+type TermViolation struct {
+	Source   string
+	Expected string
+}
+
+func (v TermViolation) String() string {
+	if v.Source == v.Expected {
+		return v.Source + "（应保持原文）"
+	}
+	return fmt.Sprintf("%s（应为 %s）", v.Source, v.Expected)
+}
+
+// checkTerms 检查译文是否使用了术语表规定的写法，大小写规则与 Match 一致
+func checkTerms(output string, entries []GlossaryEntry) []TermViolation {
+	var violations []TermViolation
+	for _, e := range entries {
+		expect := e.expect
+		if expect == nil {
+			expect = termRegexp(e.Expected(), e.foldCase())
+		}
+		if !expect.MatchString(output) {
+			violations = append(violations, TermViolation{Source: e.Source, Expected: e.Expected()})
+		}
+	}
+	return violations
+}
+
+// violationFeedback 重新提示时告诉模型哪些术语没有遵守
+func violationFeedback(violations []TermViolation) string {
+	var b strings.Builder
+	b.WriteString("译文没有遵守术语表：")
+	for _, v := range violations {
+		b.WriteString("\n- " + v.String())
+	}
+	b.WriteString("\n请修正后重新输出完整译文，只输出译文。")
+	return b.String()
+}
@@ -0,0 +1,488 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TMEntry 翻译记忆中的一条：原文片段、目标语言与译文
+type TMEntry struct {
+	Source     string `json:"source"`
+	SourceLang string `json:"source_lang,omitempty"`
+	TargetLang string `json:"target_lang"`
+	Target     string `json:"target"`
+	// Approved 译文已认可（TMX 导入的人工审校结果或通过质量评估），只有认可的译文会被直接复用
+	Approved  bool      `json:"approved"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TMMatch 模糊匹配结果
+type TMMatch struct {
+	*TMEntry
+	Score float64
+}
+
+// TMConfig 翻译记忆配置
+type TMConfig struct {
+	// Path JSON 存储文件，为空时只在内存中
+	Path string
+	// Threshold 模糊匹配的最低相似度，默认 0.75
+	Threshold float64
+	// MaxReferences 每次最多提供给模型的参考译文数，默认 3
+	MaxReferences int
+}
+
+// TranslationMemory 本地翻译记忆
+// 完全相同的原文且译文已认可时直接复用、不再请求模型；未认可的模型译文与相似度超过阈值的条目只作为参考译文放进提示词
+type TranslationMemory struct {
+	path          string
+	threshold     float64
+	maxReferences int
+
+	mu      sync.RWMutex
+	entries map[string]map[string]*TMEntry // 目标语言 -> 规范化原文 -> 条目
+	dirty   bool
+}
+
+// OpenTranslationMemory 打开（或新建）翻译记忆
+func OpenTranslationMemory(cfg *TMConfig) (*TranslationMemory, error) {
+	if cfg == nil {
+		cfg = &TMConfig{}
+	}
+	tm := &TranslationMemory{
+		path:          cfg.Path,
+		threshold:     cfg.Threshold,
+		maxReferences: cfg.MaxReferences,
+		entries:       make(map[string]map[string]*TMEntry),
+	}
+	if tm.threshold <= 0 {
+		tm.threshold = 0.75
+	}
+	if tm.maxReferences <= 0 {
+		tm.maxReferences = 3
+	}
+	if tm.path == "" {
+		return tm, nil
+	}
+
+	b, err := os.ReadFile(tm.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tm, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stored []*TMEntry
+	if err := json.Unmarshal(b, &stored); err != nil {
+		return nil, fmt.Errorf("load translation memory %s: %w", tm.path, err)
+	}
+	for _, e := range stored {
+		tm.put(e)
+	}
+	tm.dirty = false
+	return tm, nil
+}
+
+// normalizeSegment 规范化原文作为 key：去掉首尾空白，连续空白合并为一个空格
+func normalizeSegment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func (tm *TranslationMemory) put(e *TMEntry) {
+	lang := normalizeLang(e.TargetLang)
+	byLang, ok := tm.entries[lang]
+	if !ok {
+		byLang = make(map[string]*TMEntry)
+		tm.entries[lang] = byLang
+	}
+	e.TargetLang = lang
+	if e.UpdatedAt.IsZero() {
+		e.UpdatedAt = time.Now()
+	}
+	byLang[normalizeSegment(e.Source)] = e
+	tm.dirty = true
+}
+
+// Add 记录一条未经审校的模型译文，只作为参考译文使用；不会覆盖已认可的译文
+func (tm *TranslationMemory) Add(source, targetLang, target string) {
+	tm.add(source, targetLang, target, false)
+}
+
+// Approve 记录一条认可的译文（人工审校或通过质量评估），之后相同原文直接复用
+func (tm *TranslationMemory) Approve(source, targetLang, target string) {
+	tm.add(source, targetLang, target, true)
+}
+
+func (tm *TranslationMemory) add(source, targetLang, target string, approved bool) {
+	if tm == nil || strings.TrimSpace(source) == "" || strings.TrimSpace(target) == "" {
+		return
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if !approved {
+		if old, ok := tm.entries[normalizeLang(targetLang)][normalizeSegment(source)]; ok && old.Approved {
+			return
+		}
+	}
+	tm.put(&TMEntry{Source: source, TargetLang: targetLang, Target: target, Approved: approved, UpdatedAt: time.Now()})
+}
+
+// Lookup 精确匹配（忽略空白差异），只返回已认可的译文
+func (tm *TranslationMemory) Lookup(source, targetLang string) (string, bool) {
+	if tm == nil {
+		return "", false
+	}
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	e, ok := tm.entries[normalizeLang(targetLang)][normalizeSegment(source)]
+	if !ok || !e.Approved {
+		return "", false
+	}
+	return e.Target, true
+}
+
+// Fuzzy 返回相似度不低于阈值的条目，按相似度从高到低，最多 MaxReferences 条
+// 原文完全相同但未认可的条目也会返回，已认可的由 Lookup 直接复用
+func (tm *TranslationMemory) Fuzzy(source, targetLang string) []TMMatch {
+	if tm == nil {
+		return nil
+	}
+	src := []rune(normalizeSegment(source))
+	if len(src) == 0 {
+		return nil
+	}
+
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	var matches []TMMatch
+	for key, e := range tm.entries[normalizeLang(targetLang)] {
+		cand := []rune(key)
+		// 长度差距过大时相似度不可能达到阈值，跳过编辑距离计算
+		shorter, longer := min(len(src), len(cand)), max(len(src), len(cand))
+		if float64(shorter)/float64(longer) < tm.threshold {
+			continue
+		}
+		score := similarity(src, cand)
+		if score >= tm.threshold && (score < 1 || !e.Approved) {
+			matches = append(matches, TMMatch{TMEntry: e, Score: score})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > tm.maxReferences {
+		matches = matches[:tm.maxReferences]
+	}
+	return matches
+}
+
+// similarity 基于编辑距离的相似度：1 - 距离/较长者长度
+func similarity(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(b)])/float64(max(len(a), len(b)))
+}
+
+// Len 条目总数
+func (tm *TranslationMemory) Len() int {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	n := 0
+	for _, byLang := range tm.entries {
+		n += len(byLang)
+	}
+	return n
+}
+
+// sorted 按目标语言、原文排序的全部条目，保证导出结果稳定
+func (tm *TranslationMemory) sorted() []*TMEntry {
+	var out []*TMEntry
+	for _, byLang := range tm.entries {
+		for _, e := range byLang {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TargetLang != out[j].TargetLang {
+			return out[i].TargetLang < out[j].TargetLang
+		}
+		return out[i].Source < out[j].Source
+	})
+	return out
+}
+
+// Save 写回 JSON 文件；没有变更或未配置 Path 时什么都不做
+func (tm *TranslationMemory) Save() error {
+	if tm == nil || tm.path == "" {
+		return nil
+	}
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if !tm.dirty {
+		return nil
+	}
+	// 不转义 <、>、&，方便直接阅读和编辑
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(tm.sorted()); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(tm.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(tm.path, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	tm.dirty = false
+	return nil
+}
+
+// referencePrompt 把模糊匹配到的译文作为参考注入系统提示词
+func referencePrompt(matches []TMMatch) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n翻译记忆中的相似句（仅供参考，保持用词与风格一致，按实际原文翻译）：")
+	for _, m := range matches {
+		note := ""
+		if !m.Approved {
+			note = "（未审校）"
+		}
+		fmt.Fprintf(&b, "\n- 原文：%s\n  译文%s：%s", m.Source, note, m.Target)
+	}
+	return b.String()
+}
+
+// ---------- TMX ----------
+
+// tmxDoc TMX 1.4 文档；xml:lang 在解析时按本地名 lang 匹配，兼容 TMX 1.1 的 lang 属性
+type tmxDoc struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Header  tmxHeader `xml:"header"`
+	TUs     []struct {
+		TUVs []struct {
+			Lang string `xml:"lang,attr"`
+			Seg  string `xml:"seg"`
+		} `xml:"tuv"`
+	} `xml:"body>tu"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+}
+
+// tmxOut 导出用的结构，xml:lang 直接写字面属性名
+type tmxOut struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	TUs     []tmxTU   `xml:"body>tu"`
+}
+
+type tmxTU struct {
+	ChangeDate string   `xml:"changedate,attr,omitempty"`
+	TUVs       []tmxTUV `xml:"tuv"`
+}
+
+type tmxTUV struct {
+	Lang string `xml:"xml:lang,attr"`
+	Seg  string `xml:"seg"`
+}
+
+// ImportTMX 导入 TMX，返回导入的条目数；与已有条目相同的原文以 TMX 中的译文为准（视为人工审校结果）
+func (tm *TranslationMemory) ImportTMX(r io.Reader) (int, error) {
+	var doc tmxDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return 0, fmt.Errorf("parse tmx: %w", err)
+	}
+	srcLang := normalizeLang(doc.Header.SrcLang)
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	n := 0
+	for _, tu := range doc.TUs {
+		if len(tu.TUVs) < 2 {
+			continue
+		}
+		// srclang 为 *all* 或找不到对应 tuv 时，以第一个 tuv 为原文
+		src := 0
+		for i, tuv := range tu.TUVs {
+			if normalizeLang(tuv.Lang) == srcLang {
+				src = i
+				break
+			}
+		}
+		for i, tuv := range tu.TUVs {
+			if i == src || strings.TrimSpace(tuv.Seg) == "" {
+				continue
+			}
+			tm.put(&TMEntry{
+				Source:     tu.TUVs[src].Seg,
+				SourceLang: normalizeLang(tu.TUVs[src].Lang),
+				TargetLang: tuv.Lang,
+				Target:     tuv.Seg,
+				Approved:   true,
+				UpdatedAt:  time.Now(),
+			})
+			n++
+		}
+	}
+	return n, nil
+}
+
+// ExportTMX 导出为 TMX 1.4，srcLang 用于没有记录原文语言的条目（如 zh、en）
+func (tm *TranslationMemory) ExportTMX(w io.Writer, srcLang string) error {
+	tm.mu.RLock()
+	entries := tm.sorted()
+	tm.mu.RUnlock()
+
+	srcLang = normalizeLang(srcLang)
+	out := tmxOut{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "einotelos-translator",
+			CreationToolVersion: "1.0",
+			SegType:             "block",
+			OTMF:                "json",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+		},
+	}
+	for _, e := range entries {
+		lang := e.SourceLang
+		if lang == "" {
+			lang = srcLang
+		}
+		out.TUs = append(out.TUs, tmxTU{
+			ChangeDate: e.UpdatedAt.UTC().Format("20060102T150405Z"),
+			TUVs: []tmxTUV{
+				{Lang: lang, Seg: e.Source},
+				{Lang: e.TargetLang, Seg: e.Target},
+			},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// langCodes 常见语言名到 ISO 639-1 代码，让 "English"、"英文"、"en-US" 落到同一个 key
+var langCodes = map[string]string{
+	"中文": "zh", "汉语": "zh", "简体中文": "zh", "chinese": "zh", "zh-cn": "zh", "zh-hans": "zh",
+	"繁体中文": "zh-tw", "zh-tw": "zh-tw", "zh-hk": "zh-tw", "zh-hant": "zh-tw",
+	"英文": "en", "英语": "en", "english": "en",
+	"日文": "ja", "日语": "ja", "japanese": "ja",
+	"韩文": "ko", "韩语": "ko", "korean": "ko",
+	"法文": "fr", "法语": "fr", "french": "fr",
+	"德文": "de", "德语": "de", "german": "de",
+	"西班牙文": "es", "西班牙语": "es", "spanish": "es",
+	"俄文": "ru", "俄语": "ru", "russian": "ru",
+}
+
+// normalizeLang 把语言名规范化为代码（en-US -> en），未知语言原样转小写
+func normalizeLang(lang string) string {
+	l := strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := langCodes[l]; ok {
+		return code
+	}
+	if i := strings.IndexAny(l, "-_"); i > 0 {
+		return l[:i]
+	}
+	return l
+}
+
+// main02 翻译记忆示例：模型译文先作为未审校的参考，认可后再翻译同一句直接命中记忆，最后导出 TMX 供译者审校
+func main02() {
+	memory, err := OpenTranslationMemory(&TMConfig{Path: ".cache/translation_memory.json"})
+	if err != nil {
+		log.Fatalf("打开翻译记忆失败: %v", err)
+	}
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey: os.Getenv("DEEPSEEK_API_KEY"),
+		Memory: memory,
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	ctx := context.Background()
+	text := "Eino 是一个强大的 AI 开发框架"
+	for i, src := range []string{
+		text, // 模型译文写入记忆，未审校
+		text, // 未审校的译文只作为参考，仍会请求模型
+		text, // 认可后精确命中
+		"Eino 是一个非常强大的 AI 开发框架", // 模糊命中，作为参考译文
+	} {
+		res, err := translator.TranslateDetail(ctx, src, "English")
+		if err != nil {
+			log.Printf("翻译失败: %v", err)
+			continue
+		}
+		fmt.Printf("原文: %s\n翻译: %s（来自记忆: %v）\n\n", src, res.Text, res.FromMemory)
+		if i == 1 {
+			// 模拟译者审校后认可这条译文
+			memory.Approve(src, "English", res.Text)
+		}
+	}
+
+	if err := memory.Save(); err != nil {
+		log.Fatalf("保存翻译记忆失败: %v", err)
+	}
+	f, err := os.Create(".cache/translation_memory.tmx")
+	if err != nil {
+		log.Fatalf("导出 TMX 失败: %v", err)
+	}
+	defer f.Close()
+	if err := memory.ExportTMX(f, "zh"); err != nil {
+		log.Fatalf("导出 TMX 失败: %v", err)
+	}
+	fmt.Printf("翻译记忆共 %d 条，已导出 .cache/translation_memory.tmx\n", memory.Len())
+}
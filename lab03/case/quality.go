@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// QualityOptions 译文质量评估选项
+type QualityOptions struct {
+	// Threshold 充分性或流畅度任一低于该分数（1~5 分）即标记为需要人工审校，默认 3.5
+	Threshold float64
+	// Concurrency 并发评估的段数，默认 4
+	Concurrency int
+}
+
+// SegmentPair 一段原文与译文
+type SegmentPair struct {
+	Source      string
+	Translation string
+}
+
+// QualityScore 单段的评估结果
+type QualityScore struct {
+	Source          string  `json:"source"`
+	Translation     string  `json:"translation"`
+	BackTranslation string  `json:"back_translation"`
+	Adequacy        float64 `json:"adequacy"` // 充分性：意思是否完整准确
+	Fluency         float64 `json:"fluency"`  // 流畅度：译文是否自然通顺
+	Comment         string  `json:"comment,omitempty"`
+	Flagged         bool    `json:"flagged"`
+	// Error 回译或评审失败的原因；失败的段落同样标记为需要人工审校，不计入平均分
+	Error string `json:"error,omitempty"`
+}
+
+// QualityReport 一批译文的审校报告
+type QualityReport struct {
+	TargetLang  string          `json:"target_lang"`
+	Threshold   float64         `json:"threshold"`
+	Segments    []*QualityScore `json:"segments"`
+	Flagged     int             `json:"flagged"`
+	Failed      int             `json:"failed"`
+	AvgAdequacy float64         `json:"avg_adequacy"`
+	AvgFluency  float64         `json:"avg_fluency"`
+}
+
+// langNames 回译提示词中使用的语言名
+var langNames = map[string]string{
+	"zh": "中文", "zh-tw": "繁体中文", "en": "英文", "ja": "日文", "ko": "韩文",
+	"fr": "法文", "de": "德文", "es": "西班牙文", "pt": "葡萄牙文", "it": "意大利文", "ru": "俄文",
+}
+
+// EstimateQuality 评估一段译文：先把译文回译成原文语言，再让模型对照原文、译文、回译打分
+func (t *Translator) EstimateQuality(ctx context.Context, source, translation, targetLang string, threshold float64) (*QualityScore, error) {
+	if strings.TrimSpace(source) == "" || strings.TrimSpace(translation) == "" {
+		return nil, errors.New("empty source or translation")
+	}
+	if threshold <= 0 {
+		threshold = 3.5
+	}
+
+	// 回译不走翻译记忆和术语表，避免回译结果污染记忆
+	sourceLang := langNames[DetectLanguage(source)]
+	if sourceLang == "" {
+		sourceLang = "原文所用的语言"
+	}
+	back, err := t.generate(ctx, []*schema.Message{
+		schema.SystemMessage(fmt.Sprintf(
+			"你是一个专业翻译引擎。将用户输入逐句直译成%s，尽量贴近字面，不要润色；只输出译文。", sourceLang)),
+		schema.UserMessage(translation),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("back-translate: %w", err)
+	}
+
+	judge, err := t.generate(ctx, []*schema.Message{
+		schema.SystemMessage(
+			"你是资深的翻译审校。根据原文、译文以及译文的回译，从两个维度给译文打 1~5 分（可带一位小数）：\n" +
+				"- adequacy（充分性）：译文是否完整、准确地传达了原文意思，有无漏译、错译、增译；回译与原文的差异可作为参考\n" +
+				"- fluency（流畅度）：译文在目标语言中是否自然通顺、符合表达习惯\n" +
+				"只输出 JSON，格式：{\"adequacy\": 4.5, \"fluency\": 4, \"comment\": \"主要问题，没有则留空\"}"),
+		schema.UserMessage(fmt.Sprintf("目标语言：%s\n\n原文：\n%s\n\n译文：\n%s\n\n回译：\n%s",
+			targetLang, source, translation, back)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("judge: %w", err)
+	}
+
+	score := &QualityScore{Source: source, Translation: translation, BackTranslation: back}
+	if err := parseJudge(judge, score); err != nil {
+		return nil, err
+	}
+	score.Flagged = score.Adequacy < threshold || score.Fluency < threshold
+	return score, nil
+}
+
+// parseJudge 解析评审模型输出的 JSON，容忍 ```json 代码块和前后多余文字
+func parseJudge(resp string, score *QualityScore) error {
+	start, end := strings.Index(resp, "{"), strings.LastIndex(resp, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("judge returned no json: %q", resp)
+	}
+	var v struct {
+		Adequacy float64 `json:"adequacy"`
+		Fluency  float64 `json:"fluency"`
+		Comment  string  `json:"comment"`
+	}
+	if err := json.Unmarshal([]byte(resp[start:end+1]), &v); err != nil {
+		return fmt.Errorf("parse judge output: %w", err)
+	}
+	if v.Adequacy < 1 || v.Adequacy > 5 || v.Fluency < 1 || v.Fluency > 5 {
+		return fmt.Errorf("judge scores out of range: adequacy=%v fluency=%v", v.Adequacy, v.Fluency)
+	}
+	score.Adequacy, score.Fluency, score.Comment = v.Adequacy, v.Fluency, v.Comment
+	return nil
+}
+
+// Review 并发评估一批译文，生成审校报告
+// 单段回译、评审失败或输出无法解析时记为需要人工审校并继续，只有 ctx 取消时返回错误
+func (t *Translator) Review(ctx context.Context, pairs []SegmentPair, targetLang string, opts *QualityOptions) (*QualityReport, error) {
+	o := QualityOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Threshold <= 0 {
+		o.Threshold = 3.5
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+
+	report := &QualityReport{TargetLang: targetLang, Threshold: o.Threshold, Segments: make([]*QualityScore, len(pairs))}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, o.Concurrency)
+	)
+	for i, p := range pairs {
+		wg.Add(1)
+		go func(i int, p SegmentPair) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			score, err := t.EstimateQuality(ctx, p.Source, p.Translation, targetLang, o.Threshold)
+			if err != nil {
+				score = &QualityScore{Source: p.Source, Translation: p.Translation, Flagged: true, Error: err.Error()}
+			}
+			report.Segments[i] = score
+		}(i, p)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, s := range report.Segments {
+		if s.Flagged {
+			report.Flagged++
+		}
+		if s.Error != "" {
+			report.Failed++
+			continue
+		}
+		report.AvgAdequacy += s.Adequacy
+		report.AvgFluency += s.Fluency
+	}
+	if n := float64(len(report.Segments) - report.Failed); n > 0 {
+		report.AvgAdequacy /= n
+		report.AvgFluency /= n
+	}
+	return report, nil
+}
+
+// WriteMarkdown 输出 Markdown 格式的审校报告：汇总 + 需要人工审校的段落
+func (r *QualityReport) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# 译文审校报告（%s）\n\n", r.TargetLang)
+	fmt.Fprintf(&b, "- 段落数：%d\n- 需要人工审校：%d（任一分数低于 %.1f，其中评估失败 %d）\n- 平均充分性：%.2f\n- 平均流畅度：%.2f\n",
+		len(r.Segments), r.Flagged, r.Threshold, r.Failed, r.AvgAdequacy, r.AvgFluency)
+	if r.Flagged == 0 {
+		b.WriteString("\n全部段落通过。\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	b.WriteString("\n## 需要人工审校的段落\n")
+	for i, s := range r.Segments {
+		if !s.Flagged {
+			continue
+		}
+		if s.Error != "" {
+			fmt.Fprintf(&b, "\n### 段落 %d（评估失败）\n\n", i+1)
+			fmt.Fprintf(&b, "- 原文：%s\n- 译文：%s\n- 原因：%s\n", oneLine(s.Source), oneLine(s.Translation), oneLine(s.Error))
+			continue
+		}
+		fmt.Fprintf(&b, "\n### 段落 %d（充分性 %.1f / 流畅度 %.1f）\n\n", i+1, s.Adequacy, s.Fluency)
+		fmt.Fprintf(&b, "- 原文：%s\n- 译文：%s\n- 回译：%s\n", oneLine(s.Source), oneLine(s.Translation), oneLine(s.BackTranslation))
+		if s.Comment != "" {
+			fmt.Fprintf(&b, "- 问题：%s\n", s.Comment)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// main06 质量评估示例：翻译一批句子后回译 + 评审打分，输出审校报告
+func main06() {
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
+		Retries: 2,
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	ctx := context.Background()
+	var pairs []SegmentPair
+	for _, text := range []string{
+		"Eino 是字节跳动开源的 LLM 应用开发框架。",
+		"通过 callbacks 可以观测每一次模型调用的输入输出与耗时。",
+	} {
+		out, err := translator.Translate(ctx, text, "English")
+		if err != nil {
+			log.Fatalf("翻译失败: %v", err)
+		}
+		pairs = append(pairs, SegmentPair{Source: text, Translation: out})
+	}
+	// 人为放一段错译，演示被标记出来
+	pairs = append(pairs, SegmentPair{Source: "请在提交前运行全部测试。", Translation: "Please skip the tests after submitting."})
+
+	report, err := translator.Review(ctx, pairs, "English", nil)
+	if err != nil {
+		log.Fatalf("评估失败: %v", err)
+	}
+	if err := report.WriteMarkdown(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
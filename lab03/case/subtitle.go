@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SubtitleFormat 字幕格式
+type SubtitleFormat string
+
+const (
+	SubSRT SubtitleFormat = "srt"
+	SubVTT SubtitleFormat = "vtt"
+)
+
+// SubtitleFormatFromPath 根据扩展名推断字幕格式
+func SubtitleFormatFromPath(path string) (SubtitleFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".srt":
+		return SubSRT, nil
+	case ".vtt":
+		return SubVTT, nil
+	default:
+		return "", fmt.Errorf("unsupported subtitle file: %s", path)
+	}
+}
+
+// Cue 一条字幕
+type Cue struct {
+	// Index SRT 序号；WebVTT 中为可选的 cue 标识
+	Index string
+	Start time.Duration
+	End   time.Duration
+	// Settings WebVTT 时间轴后的位置设置，如 align:start line:90%
+	Settings string
+	Lines    []string
+	// Notes 出现在本条之前的 NOTE / STYLE / REGION 块（仅 WebVTT），原样保留
+	Notes []string
+}
+
+// Text 多行字幕合并为一行
+func (c *Cue) Text() string {
+	return strings.Join(c.Lines, " ")
+}
+
+// Subtitle 解析后的字幕文件
+type Subtitle struct {
+	Format SubtitleFormat
+	// Header WebVTT 的 WEBVTT 头部块
+	Header string
+	Cues   []*Cue
+	// Trailer 最后一条字幕之后的 NOTE 等块
+	Trailer []string
+}
+
+var timestampRe = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{2})[.,](\d{3})$`)
+
+func parseTimestamp(s string) (time.Duration, error) {
+	m := timestampRe.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var h int
+	if m[1] != "" {
+		h, _ = strconv.Atoi(m[1])
+	}
+	mm, _ := strconv.Atoi(m[2])
+	sec, _ := strconv.Atoi(m[3])
+	ms, _ := strconv.Atoi(m[4])
+	return time.Duration(h)*time.Hour + time.Duration(mm)*time.Minute +
+		time.Duration(sec)*time.Second + time.Duration(ms)*time.Millisecond, nil
+}
+
+func formatTimestamp(d time.Duration, sep string) string {
+	h := d / time.Hour
+	d -= h * time.Hour
+	m := d / time.Minute
+	d -= m * time.Minute
+	s := d / time.Second
+	d -= s * time.Second
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, d/time.Millisecond)
+}
+
+// parseTiming 解析 "00:00:01,000 --> 00:00:02,500 align:start"
+func parseTiming(line string) (start, end time.Duration, settings string, err error) {
+	left, right, ok := strings.Cut(line, "-->")
+	if !ok {
+		return 0, 0, "", fmt.Errorf("invalid timing line %q", line)
+	}
+	fields := strings.Fields(right)
+	if len(fields) == 0 {
+		return 0, 0, "", fmt.Errorf("invalid timing line %q", line)
+	}
+	if start, err = parseTimestamp(left); err != nil {
+		return 0, 0, "", err
+	}
+	if end, err = parseTimestamp(fields[0]); err != nil {
+		return 0, 0, "", err
+	}
+	return start, end, strings.Join(fields[1:], " "), nil
+}
+
+// subtitleBlocks 按空行切块，兼容 BOM 与 \r\n
+func subtitleBlocks(content string) [][]string {
+	content = strings.TrimPrefix(content, "\uFEFF")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	var blocks [][]string
+	var cur []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(cur) > 0 {
+				blocks = append(blocks, cur)
+				cur = nil
+			}
+			continue
+		}
+		cur = append(cur, strings.TrimRight(line, " \t"))
+	}
+	if len(cur) > 0 {
+		blocks = append(blocks, cur)
+	}
+	return blocks
+}
+
+// ParseSubtitle 解析 SRT / WebVTT 字幕
+func ParseSubtitle(content string, format SubtitleFormat) (*Subtitle, error) {
+	sub := &Subtitle{Format: format}
+	blocks := subtitleBlocks(content)
+
+	var notes []string
+	for i, block := range blocks {
+		if format == SubVTT {
+			if i == 0 {
+				if !strings.HasPrefix(block[0], "WEBVTT") {
+					return nil, fmt.Errorf("invalid webvtt: missing WEBVTT header")
+				}
+				sub.Header = strings.Join(block, "\n")
+				continue
+			}
+			if first := block[0]; first == "NOTE" || strings.HasPrefix(first, "NOTE ") ||
+				first == "STYLE" || first == "REGION" {
+				notes = append(notes, strings.Join(block, "\n"))
+				continue
+			}
+		}
+
+		cue := &Cue{Notes: notes}
+		notes = nil
+		timing := 0
+		if !strings.Contains(block[0], "-->") {
+			cue.Index = block[0]
+			timing = 1
+		}
+		if timing >= len(block) {
+			return nil, fmt.Errorf("cue %d: missing timing line", len(sub.Cues)+1)
+		}
+		var err error
+		if cue.Start, cue.End, cue.Settings, err = parseTiming(block[timing]); err != nil {
+			return nil, fmt.Errorf("cue %d: %w", len(sub.Cues)+1, err)
+		}
+		cue.Lines = append([]string(nil), block[timing+1:]...)
+		sub.Cues = append(sub.Cues, cue)
+	}
+	sub.Trailer = notes
+	return sub, nil
+}
+
+// String 按原格式输出字幕文本
+func (s *Subtitle) String() string {
+	var b strings.Builder
+	sep := ","
+	if s.Format == SubVTT {
+		sep = "."
+		header := s.Header
+		if header == "" {
+			header = "WEBVTT"
+		}
+		fmt.Fprintf(&b, "%s\n\n", header)
+	}
+
+	for i, cue := range s.Cues {
+		for _, note := range cue.Notes {
+			fmt.Fprintf(&b, "%s\n\n", note)
+		}
+		index := cue.Index
+		if s.Format == SubSRT && index == "" {
+			index = strconv.Itoa(i + 1)
+		}
+		if index != "" {
+			fmt.Fprintln(&b, index)
+		}
+		timing := formatTimestamp(cue.Start, sep) + " --> " + formatTimestamp(cue.End, sep)
+		if cue.Settings != "" {
+			timing += " " + cue.Settings
+		}
+		fmt.Fprintln(&b, timing)
+		for _, line := range cue.Lines {
+			fmt.Fprintln(&b, line)
+		}
+		fmt.Fprintln(&b)
+	}
+	for _, note := range s.Trailer {
+		fmt.Fprintf(&b, "%s\n\n", note)
+	}
+	return b.String()
+}
+
+// WriteTo 把字幕写入 w
+func (s *Subtitle) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, s.String())
+	return int64(n), err
+}
+
+// wrapSubtitle 按每行最多 limit 个字符折行
+// 优先在空格处断开；中日韩文字之间、标点之后也可断开
+func wrapSubtitle(text string, limit int) []string {
+	text = strings.Join(strings.Fields(text), " ")
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var lines []string
+	runes := []rune(text)
+	for len(runes) > limit {
+		cut := -1 // 断行位置：本行取 runes[:cut]
+		for i := limit; i > 0; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+			if breakAfter(runes[i-1]) && !unicode.IsPunct(runes[i]) {
+				cut = i
+				break
+			}
+		}
+		if cut <= 0 {
+			cut = limit // 没有合适的断点（超长单词），硬断
+		}
+		lines = append(lines, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	if len(runes) > 0 {
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
+
+// breakAfter 该字符之后可以断行：中日韩文字或标点
+func breakAfter(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		unicode.IsPunct(r) && r >= utf8.RuneSelf
+}
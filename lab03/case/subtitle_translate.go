@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SubtitleOptions 字幕翻译选项
+type SubtitleOptions struct {
+	// BatchSize 每次请求翻译的字幕条数，默认 20
+	BatchSize int
+	// ContextCues 附带的前文条数（原文+译文），帮助保持人名、术语、语气一致，默认 3，小于 0 表示不带前文
+	ContextCues int
+	// MaxLineChars 每行最多字符数，超出时自动折行，默认 42（中日韩目标语言建议 16~20）
+	MaxLineChars int
+}
+
+var (
+	batchLineRe = regexp.MustCompile(`(?m)^\s*\[(\d+)\]\s*(.*?)\s*$`)
+	// voiceTagRe WebVTT 行首的说话人标签，如 <v Coach>，翻译时去掉、输出时原样加回
+	voiceTagRe = regexp.MustCompile(`^<v[.\s][^>]*>\s*`)
+)
+
+// splitVoice 拆出字幕文本开头的说话人标签
+func splitVoice(text string) (tag, rest string) {
+	tag = voiceTagRe.FindString(text)
+	return strings.TrimSpace(tag), text[len(tag):]
+}
+
+// SubtitleResult 字幕翻译结果
+type SubtitleResult struct {
+	// Subtitle 译好的字幕，时间轴、序号、WebVTT 设置与 NOTE 块保持不变
+	Subtitle *Subtitle
+	// Violations 未遵守术语表的字幕条目，按出现顺序
+	Violations []CueViolation
+}
+
+// CueViolation 一条字幕的译文中缺失的术语
+type CueViolation struct {
+	// Cue 字幕在文件中的位置，从 1 开始
+	Cue        int
+	Violations []TermViolation
+}
+
+// TranslateSubtitle 翻译字幕，返回新的字幕与术语检查结果
+// 字幕按批次顺序翻译，每批附带前几条的原文和译文作为上下文
+func (t *Translator) TranslateSubtitle(ctx context.Context, sub *Subtitle, targetLang string, opts *SubtitleOptions) (*SubtitleResult, error) {
+	if strings.TrimSpace(targetLang) == "" {
+		return nil, errors.New("empty target language")
+	}
+	o := SubtitleOptions{}
+	if opts != nil {
+		o = *opts
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 20
+	}
+	if o.ContextCues < 0 {
+		o.ContextCues = 0
+	} else if o.ContextCues == 0 {
+		o.ContextCues = 3
+	}
+	if o.MaxLineChars <= 0 {
+		o.MaxLineChars = 42
+	}
+
+	out := &Subtitle{Format: sub.Format, Header: sub.Header, Trailer: sub.Trailer}
+	translated := make([]string, len(sub.Cues))
+	violations := make([][]TermViolation, len(sub.Cues))
+	for start := 0; start < len(sub.Cues); start += o.BatchSize {
+		end := min(start+o.BatchSize, len(sub.Cues))
+		ctxStart := max(0, start-o.ContextCues)
+		if err := t.translateCues(ctx, sub.Cues[ctxStart:start], translated[ctxStart:start],
+			sub.Cues[start:end], translated[start:end], violations[start:end], targetLang, o.MaxLineChars); err != nil {
+			return nil, fmt.Errorf("cues %d-%d: %w", start+1, end, err)
+		}
+	}
+
+	for i, cue := range sub.Cues {
+		c := *cue
+		c.Lines = wrapSubtitle(translated[i], o.MaxLineChars)
+		if tag, _ := splitVoice(cue.Text()); tag != "" {
+			c.Lines[0] = tag + c.Lines[0]
+		}
+		out.Cues = append(out.Cues, &c)
+	}
+	result := &SubtitleResult{Subtitle: out}
+	for i, v := range violations {
+		if len(v) > 0 {
+			result.Violations = append(result.Violations, CueViolation{Cue: i + 1, Violations: v})
+		}
+	}
+	return result, nil
+}
+
+// translateCues 翻译一批字幕，译文写入 dst，未遵守术语表的术语写入 violations
+// 模型返回的条数对不上时重试一次，仍不对则逐条翻译
+func (t *Translator) translateCues(ctx context.Context, prev []*Cue, prevDst []string, cues []*Cue, dst []string, violations [][]TermViolation, targetLang string, maxLineChars int) error {
+	var (
+		b       strings.Builder
+		pending []int // 需要请求模型的条目（批内下标）
+	)
+	texts := make([]string, len(cues))
+	for i, cue := range cues {
+		_, text := splitVoice(cue.Text())
+		texts[i] = text
+		if !hasWords(text) {
+			dst[i] = text
+			continue
+		}
+		if target, ok := t.memory.Lookup(text, targetLang); ok {
+			dst[i] = target
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if len(prev) > 0 {
+		b.WriteString("前文（仅供参考，不要输出）：\n")
+		for i, cue := range prev {
+			_, text := splitVoice(cue.Text())
+			fmt.Fprintf(&b, "原文：%s\n译文：%s\n", text, prevDst[i])
+		}
+		b.WriteString("\n待翻译：\n")
+	}
+	for n, i := range pending {
+		fmt.Fprintf(&b, "[%d] %s\n", n+1, texts[i])
+	}
+
+	system := fmt.Sprintf(
+		"你是专业的视频字幕翻译。将字幕翻译成%s。"+
+			"输入每行格式为 [序号] 字幕文本；输出必须保留相同的序号，每条一行，条数与输入一致，不要合并或拆分条目；"+
+			"译文口语化、简洁，每条尽量不超过 %d 个字符；结合前文保持人名、术语和语气一致；只输出译文，不要解释。",
+		targetLang, maxLineChars*2,
+	)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		// 整批的检查结果无法定位到具体字幕，下面逐条对照本条原文命中的术语重新检查
+		resp, _, err := t.generateWithTerms(ctx, system, b.String())
+		if err != nil {
+			return err
+		}
+		lines, ok := parseBatch(resp, len(pending))
+		if !ok {
+			continue
+		}
+		for n, i := range pending {
+			dst[i] = lines[n]
+			violations[i] = checkTerms(lines[n], t.glossary.Match(texts[i]))
+			// 只记住符合术语表的译文
+			if len(violations[i]) == 0 {
+				t.memory.Add(texts[i], targetLang, lines[n])
+			}
+		}
+		return nil
+	}
+
+	// 批量结果无法对齐时退回逐条翻译，保证每条字幕都有对应译文
+	for _, i := range pending {
+		res, err := t.TranslateDetail(ctx, texts[i], targetLang)
+		if err != nil {
+			return err
+		}
+		dst[i] = res.Text
+		violations[i] = res.Violations
+	}
+	return nil
+}
+
+// parseBatch 按 [序号] 取出 n 条译文，缺条、多条或空译文时 ok 为 false
+func parseBatch(resp string, n int) ([]string, bool) {
+	lines := make([]string, n)
+	matches := batchLineRe.FindAllStringSubmatch(resp, -1)
+	if len(matches) != n {
+		return nil, false
+	}
+	for _, m := range matches {
+		k, _ := strconv.Atoi(m[1])
+		if k < 1 || k > n || lines[k-1] != "" || m[2] == "" {
+			return nil, false
+		}
+		lines[k-1] = m[2]
+	}
+	return lines, true
+}
+
+// TranslateSubtitleFile 翻译 SRT / WebVTT 文件并写入 dst，格式按 src 扩展名判断
+func (t *Translator) TranslateSubtitleFile(ctx context.Context, src, dst, targetLang string, opts *SubtitleOptions) (*SubtitleResult, error) {
+	format, err := SubtitleFormatFromPath(src)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(src)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := ParseSubtitle(string(b), format)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", src, err)
+	}
+	res, err := t.TranslateSubtitle(ctx, sub, targetLang, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(dst, []byte(res.Subtitle.String()), 0o644); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// main05 字幕翻译示例：篮球教学视频的 SRT 字幕译成中文
+func main05() {
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey:   os.Getenv("DEEPSEEK_API_KEY"),
+		Retries:  2,
+		Glossary: NewGlossary([]GlossaryEntry{{Source: "pick and roll", Target: "挡拆"}}),
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	srt := `1
+00:00:01,000 --> 00:00:03,500
+Welcome back to the court.
+Today we work on the pick and roll.
+
+2
+00:00:03,600 --> 00:00:06,000
+Keep your elbow in when you shoot.
+
+3
+00:00:06,200 --> 00:00:09,000
+Now let's run the drill again, full speed.
+`
+	sub, err := ParseSubtitle(srt, SubSRT)
+	if err != nil {
+		log.Fatalf("解析字幕失败: %v", err)
+	}
+	res, err := translator.TranslateSubtitle(context.Background(), sub, "中文", &SubtitleOptions{MaxLineChars: 16})
+	if err != nil {
+		log.Fatalf("翻译失败: %v", err)
+	}
+	fmt.Print(res.Subtitle.String())
+	for _, v := range res.Violations {
+		fmt.Printf("第 %d 条未遵守术语表: %v\n", v.Cue, v.Violations)
+	}
+}
@@ -9,22 +9,35 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NuyoahCh/einotelos/einox/metrics"
+	"github.com/NuyoahCh/einotelos/einox/ratelimit"
 	"github.com/cloudwego/eino-ext/components/model/deepseek"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
 // Translator 基于 Deepseek 的翻译助手
 type Translator struct {
-	chatModel *deepseek.ChatModel
+	chatModel model.BaseChatModel
+	glossary  *Glossary
+	memory    *TranslationMemory
+	timeout   time.Duration // 每次 generate 的超时（含重试）
+	retries   int           // 失败后的重试次数
 }
 
 // TranslatorConfig 翻译器配置
 type TranslatorConfig struct {
-	APIKey   string
-	Model    string
-	BaseURL  string
-	Timeout  time.Duration
-	Retries  int
+	APIKey  string
+	Model   string
+	BaseURL string
+	Timeout time.Duration
+	Retries int
+	// Limiter 共享限流器，多个翻译器/批量任务共用同一份 RPM/TPM 配额；为空则不限流
+	Limiter *ratelimit.Limiter
+	// Glossary 术语表，约束术语译法与不翻译的专有名词；为空则不检查
+	Glossary *Glossary
+	// Memory 翻译记忆，精确命中时不请求模型，相似句作为参考；为空则不使用
+	Memory *TranslationMemory
 }
 
 // NewTranslator 创建一个新的翻译器实例
@@ -54,40 +67,118 @@ func NewTranslator(cfg TranslatorConfig) (*Translator, error) {
 	if err != nil {
 		return nil, err
 	}
+	t := &Translator{
+		chatModel: chatModel,
+		glossary:  cfg.Glossary,
+		memory:    cfg.Memory,
+		timeout:   cfg.Timeout,
+		retries:   cfg.Retries,
+	}
+	if cfg.Limiter != nil {
+		if t.chatModel, err = ratelimit.NewChatModel(chatModel, cfg.Limiter); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
 
-	return &Translator{chatModel: chatModel}, nil
+// Translation 翻译结果
+type Translation struct {
+	Text string
+	// Violations 重新提示后仍未遵守术语表的术语
+	Violations []TermViolation
+	// FromMemory 译文直接取自翻译记忆
+	FromMemory bool
+	// SourceLang 自动检测到的原文语言，无法判断时为空
+	SourceLang string
+	// Skipped 原文已是目标语言，未做翻译
+	Skipped bool
 }
 
 // Translate 翻译文本到目标语言
 func (t *Translator) Translate(ctx context.Context, text, targetLang string) (string, error) {
+	res, err := t.TranslateDetail(ctx, text, targetLang)
+	if err != nil {
+		return "", err
+	}
+	return res.Text, nil
+}
+
+// TranslateDetail 翻译文本，并返回术语检查结果
+func (t *Translator) TranslateDetail(ctx context.Context, text, targetLang string) (*Translation, error) {
 	text = strings.TrimSpace(text)
 	targetLang = strings.TrimSpace(targetLang)
 	if text == "" {
-		return "", errors.New("empty text")
+		return nil, errors.New("empty text")
 	}
 	if targetLang == "" {
-		return "", errors.New("empty target language")
+		return nil, errors.New("empty target language")
 	}
 
-	// 超时控制
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
+	// 原文已是目标语言时原样返回
+	sourceLang := DetectLanguage(text)
+	if sourceLang != "" && sourceLang == normalizeLang(targetLang) {
+		return &Translation{Text: text, SourceLang: sourceLang, Skipped: true}, nil
+	}
+	if target, ok := t.memory.Lookup(text, targetLang); ok {
+		return &Translation{Text: target, FromMemory: true, SourceLang: sourceLang}, nil
+	}
 
 	// 更严格的提示词：只输出译文；保留格式；不添加引号/解释
 	system := fmt.Sprintf(
 		"你是一个专业翻译引擎。将用户输入翻译成%s。"+
 			"要求：只输出译文，不要解释；保留原有换行与列表格式；不要添加引号；不要输出多余内容。",
 		targetLang,
-	)
+	) + referencePrompt(t.memory.Fuzzy(text, targetLang))
 
+	out, violations, err := t.generateWithTerms(ctx, system, text)
+	if err != nil {
+		return nil, err
+	}
+	// 符合术语表的译文作为未审校的参考写入记忆，认可前不会被直接复用
+	if len(violations) == 0 {
+		t.memory.Add(text, targetLang, out)
+	}
+	return &Translation{Text: out, Violations: violations, SourceLang: sourceLang}, nil
+}
+
+// generateWithTerms 把原文中出现的术语注入提示词，译文不合规时带上违规项重新提示一次
+func (t *Translator) generateWithTerms(ctx context.Context, system, text string) (string, []TermViolation, error) {
+	terms := t.glossary.Match(text)
 	messages := []*schema.Message{
-		schema.SystemMessage(system),
+		schema.SystemMessage(system + glossaryPrompt(terms)),
 		schema.UserMessage(text),
 	}
+	out, err := t.generate(ctx, messages)
+	if err != nil {
+		return "", nil, err
+	}
+	violations := checkTerms(out, terms)
+	if len(violations) == 0 {
+		return out, nil, nil
+	}
 
-	// 简单重试（网络抖动/429 等情况你可再细化错误判断）
+	messages = append(messages, schema.AssistantMessage(out, nil), schema.UserMessage(violationFeedback(violations)))
+	fixed, err := t.generate(ctx, messages)
+	if err != nil {
+		return "", nil, err
+	}
+	// 修正后反而更差时保留第一次的译文
+	if remaining := checkTerms(fixed, terms); len(remaining) <= len(violations) {
+		return fixed, remaining, nil
+	}
+	return out, violations, nil
+}
+
+// generate 调用模型并返回去掉首尾空白的回复，带超时与重试
+func (t *Translator) generate(ctx context.Context, messages []*schema.Message) (string, error) {
+	// 超时控制：整个重试过程共用 TranslatorConfig.Timeout
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	// 简单重试（网络抖动/429 等情况你可再细化错误判断），共 Retries+1 次
 	var lastErr error
-	for attempt := 0; attempt <= 2; attempt++ { // 默认 3 次（你可配置）
+	for attempt := 0; attempt <= t.retries; attempt++ {
 		resp, err := t.chatModel.Generate(ctx, messages)
 		if err == nil {
 			return strings.TrimSpace(resp.Content), nil
@@ -99,20 +190,47 @@ func (t *Translator) Translate(ctx context.Context, text, targetLang string) (st
 			break
 		}
 
-		// 退避
-		time.Sleep(time.Duration(attempt+1) * 300 * time.Millisecond)
+		if attempt == t.retries {
+			break
+		}
+
+		// 退避，等待期间 ctx 结束则立即返回
+		select {
+		case <-time.After(time.Duration(attempt+1) * 300 * time.Millisecond):
+		case <-ctx.Done():
+			return "", lastErr
+		}
 	}
 	return "", lastErr
 }
 
 func main() {
 	apiKey := os.Getenv("DEEPSEEK_API_KEY")
+	limiter := ratelimit.New(&ratelimit.Config{
+		Name:              "deepseek",
+		RequestsPerMinute: 60,
+		TokensPerMinute:   100000,
+	})
+	// 设置 EINOX_METRICS_ADDR 时暴露 /metrics，包含模型调用与限流器利用率
+	metricsSrv, err := metrics.ServeFromEnv()
+	if err != nil {
+		log.Fatalf("启动指标服务失败: %v", err)
+	}
+	defer metricsSrv.Close()
+	if metricsSrv != nil {
+		metrics.RegisterLimiters(metricsSrv.Registry, limiter)
+	}
+
 	translator, err := NewTranslator(TranslatorConfig{
 		APIKey:  apiKey,
 		Model:   "deepseek-chat",
 		BaseURL: "https://api.deepseek.com",
 		Timeout: 30 * time.Second,
 		Retries: 2,
+		Limiter: limiter,
+		Glossary: NewGlossary([]GlossaryEntry{
+			{Source: "框架", Target: "framework"},
+		}, "Eino", "Chain", "Graph"),
 	})
 	if err != nil {
 		log.Fatalf("创建翻译器失败: %v", err)
@@ -129,11 +247,15 @@ func main() {
 	}
 
 	for _, item := range tests {
-		result, err := translator.Translate(context.Background(), item.content, item.target)
+		result, err := translator.TranslateDetail(context.Background(), item.content, item.target)
 		if err != nil {
 			log.Printf("翻译失败: %v", err)
 			continue
 		}
-		fmt.Printf("原文: %s\n翻译: %s\n\n", item.content, result)
+		fmt.Printf("原文: %s\n翻译: %s\n", item.content, result.Text)
+		for _, v := range result.Violations {
+			fmt.Printf("术语未遵守: %s\n", v)
+		}
+		fmt.Println()
 	}
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NuyoahCh/einotelos/einox/metrics"
+	"github.com/NuyoahCh/einotelos/einox/ratelimit"
+)
+
+// LanguageResult 单个目标语言的翻译结果
+type LanguageResult struct {
+	TargetLang  string
+	Translation *Translation
+	Err         error
+}
+
+// ManyOptions 多语言翻译选项
+type ManyOptions struct {
+	// Timeout 整批翻译的总超时，0 表示只受 ctx 控制
+	Timeout time.Duration
+}
+
+// TranslateMany 把同一段文本并发翻译成多个目标语言，按 targetLangs 的顺序返回各语言的结果与错误
+// 各语言共用 Translator 上配置的限流器，单个语言失败不影响其他语言
+func (t *Translator) TranslateMany(ctx context.Context, text string, targetLangs []string, opts *ManyOptions) ([]LanguageResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("empty text")
+	}
+	if len(targetLangs) == 0 {
+		return nil, errors.New("no target language")
+	}
+	if opts != nil && opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	results := make([]LanguageResult, len(targetLangs))
+	var wg sync.WaitGroup
+	for i, lang := range targetLangs {
+		results[i].TargetLang = lang
+		wg.Add(1)
+		go func(r *LanguageResult) {
+			defer wg.Done()
+			r.Translation, r.Err = t.TranslateDetail(ctx, text, r.TargetLang)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// main03 一段文本同时翻译成多种语言，共享限流与总超时
+func main03() {
+	limiter := ratelimit.New(&ratelimit.Config{
+		Name:              "deepseek",
+		RequestsPerMinute: 30,
+	})
+	// 多个语言共用一份配额，设置 EINOX_METRICS_ADDR 时可在 /metrics 观察利用率与排队
+	metricsSrv, err := metrics.ServeFromEnv()
+	if err != nil {
+		log.Fatalf("启动指标服务失败: %v", err)
+	}
+	defer metricsSrv.Close()
+	if metricsSrv != nil {
+		metrics.RegisterLimiters(metricsSrv.Registry, limiter)
+	}
+
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
+		Limiter: limiter,
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	text := "Eino 是一个强大的 AI 开发框架"
+	results, err := translator.TranslateMany(context.Background(), text,
+		[]string{"English", "日语", "French", "中文"}, &ManyOptions{Timeout: time.Minute})
+	if err != nil {
+		log.Fatalf("翻译失败: %v", err)
+	}
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			fmt.Printf("[%s] 失败: %v\n", r.TargetLang, r.Err)
+		case r.Translation.Skipped:
+			fmt.Printf("[%s] 原文已是目标语言（检测为 %s），跳过\n", r.TargetLang, r.Translation.SourceLang)
+		default:
+			fmt.Printf("[%s] %s\n", r.TargetLang, r.Translation.Text)
+		}
+	}
+}
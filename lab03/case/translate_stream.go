@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// SentenceTranslation 流式翻译输出的一句
+type SentenceTranslation struct {
+	// Index 句子序号，从 0 开始，输出严格按序号递增
+	Index  int
+	Source string
+	Text   string
+}
+
+// StreamOptions 流式翻译选项
+type StreamOptions struct {
+	// Concurrency 同时在翻译的句子数，默认 3
+	Concurrency int
+}
+
+// TranslateStream 边读边译：从 input 中按句切分，每句单独翻译，译完即输出
+// 多句并发翻译，但输出严格保持原文顺序——前一句没译完时，后面已译完的句子会等待
+// 任意一句失败时，输出流返回该错误并结束
+func (t *Translator) TranslateStream(ctx context.Context, input *schema.StreamReader[string], targetLang string, opts *StreamOptions) (*schema.StreamReader[*SentenceTranslation], error) {
+	if input == nil {
+		return nil, errors.New("nil input stream")
+	}
+	if strings.TrimSpace(targetLang) == "" {
+		return nil, errors.New("empty target language")
+	}
+	concurrency := 3
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	type result struct {
+		item *SentenceTranslation
+		err  error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	// slots 按句子顺序排队，每句一个结果通道；容量即同时在译的句子数
+	slots := make(chan chan result, concurrency)
+
+	// 读取输入、切句、发起翻译
+	go func() {
+		defer close(slots)
+		defer input.Close()
+
+		index := 0
+		dispatch := func(sentence string) bool {
+			slot := make(chan result, 1)
+			select {
+			case slots <- slot:
+			case <-ctx.Done():
+				return false
+			}
+			go func(i int) {
+				item := &SentenceTranslation{Index: i, Source: sentence, Text: sentence}
+				if hasWords(sentence) {
+					res, err := t.TranslateDetail(ctx, sentence, targetLang)
+					if err != nil {
+						slot <- result{err: fmt.Errorf("translate sentence %d: %w", i, err)}
+						return
+					}
+					item.Text = res.Text
+				}
+				slot <- result{item: item}
+			}(index)
+			index++
+			return true
+		}
+
+		var buf strings.Builder
+		for {
+			chunk, err := input.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				slot := make(chan result, 1)
+				slot <- result{err: err}
+				select {
+				case slots <- slot:
+				case <-ctx.Done():
+				}
+				return
+			}
+			buf.WriteString(chunk)
+			sentences, rest := splitSentences(buf.String())
+			buf.Reset()
+			buf.WriteString(rest)
+			for _, s := range sentences {
+				if !dispatch(s) {
+					return
+				}
+			}
+		}
+		if last := strings.TrimSpace(buf.String()); last != "" {
+			dispatch(last)
+		}
+	}()
+
+	// 按顺序等待每句的结果并输出
+	out, w := schema.Pipe[*SentenceTranslation](concurrency)
+	go func() {
+		defer cancel()
+		defer w.Close()
+		for slot := range slots {
+			var r result
+			select {
+			case r = <-slot:
+			case <-ctx.Done():
+				w.Send(nil, ctx.Err())
+				return
+			}
+			if closed := w.Send(r.item, r.err); closed || r.err != nil {
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// sentenceEnds 句末标点；英文句点等需要后面跟空白才算句末，避免切开 3.14、e.g. 之类
+var sentenceEnds = map[rune]bool{'。': true, '！': true, '？': true, '；': true, '…': true, '.': false, '!': false, '?': false, ';': false}
+
+// closers 句末标点之后仍属于本句的右引号、右括号
+const closers = "\"'”’）)】」』"
+
+// splitSentences 从 text 中切出完整的句子，返回句子（已去掉首尾空白）与尚未结束的剩余部分
+// 换行也视为句子边界，适合字幕、聊天消息等逐行到达的输入
+func splitSentences(text string) ([]string, string) {
+	var sentences []string
+	start := 0
+	emit := func(end int) {
+		if s := strings.TrimSpace(text[start:end]); s != "" {
+			sentences = append(sentences, s)
+		}
+		start = end
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if r == '\n' {
+			emit(i)
+			continue
+		}
+		cjk, ok := sentenceEnds[r]
+		if !ok {
+			continue
+		}
+		// 连续的句末标点与右引号/括号归入本句
+		for i < len(text) {
+			next, n := utf8.DecodeRuneInString(text[i:])
+			if _, end := sentenceEnds[next]; !end && !strings.ContainsRune(closers, next) {
+				break
+			}
+			i += n
+		}
+		if cjk {
+			emit(i)
+			continue
+		}
+		// 英文标点：后面是空白才是句末；已到缓冲末尾时等更多输入再判断
+		if i < len(text) {
+			if next, _ := utf8.DecodeRuneInString(text[i:]); unicode.IsSpace(next) {
+				emit(i)
+			}
+		}
+	}
+	return sentences, text[start:]
+}
+
+// main04 流式翻译示例：模拟字幕逐段到达，每译完一句立即输出
+func main04() {
+	translator, err := NewTranslator(TranslatorConfig{
+		APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
+		Timeout: 20 * time.Second,
+		Retries: 1,
+	})
+	if err != nil {
+		log.Fatalf("创建翻译器失败: %v", err)
+	}
+
+	input, w := schema.Pipe[string](0)
+	go func() {
+		defer w.Close()
+		for _, chunk := range []string{
+			"Eino 是字节跳动开源的", " LLM 应用开发框架。它提供了组件抽象",
+			"和编排能力！开发者可以用 Chain 或 Graph", " 组织调用流程。\n欢迎试用",
+		} {
+			w.Send(chunk, nil)
+			time.Sleep(300 * time.Millisecond)
+		}
+	}()
+
+	sr, err := translator.TranslateStream(context.Background(), input, "English", nil)
+	if err != nil {
+		log.Fatalf("翻译失败: %v", err)
+	}
+	defer sr.Close()
+	for {
+		s, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("翻译失败: %v", err)
+		}
+		fmt.Printf("[%d] %s\n    %s\n", s.Index, s.Source, s.Text)
+	}
+}
@@ -5,53 +5,108 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/NuyoahCh/einotelos/einox/prompts"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
 )
 
 type UserProfile struct {
-	Name       string
-	Age        int
-	Interests  []string
-	VIPLevel   int
+	Name      string
+	Age       int
+	Interests []string
+	VIPLevel  int
+	// Bio 个人简介，可能很长，模板中截断后再使用
+	Bio string
 }
 
 func main() {
 	ctx := context.Background()
 
-	template := prompt.FromMessages(
-		schema.FString,
+	// GoTemplate：条件、循环与辅助函数（join、truncate 等）
+	goTemplate := prompt.FromMessages(
+		schema.GoTemplate,
+		prompts.NewMessage(schema.System, "你是一个智能推荐助手"),
+		prompts.NewMessage(schema.User, `用户信息：
+姓名：{{.name}}
+年龄：{{.age}}
+兴趣：{{.interests | join "、"}}
+{{- if .bio}}
+简介：{{.bio | truncate 20}}
+{{- end}}
+{{if ge .vip_level 3 -}}
+该用户是高级 VIP（{{.vip_level}} 级），优先推荐独家和付费内容。
+{{- else if gt .vip_level 0 -}}
+该用户是 VIP（{{.vip_level}} 级），可以推荐部分付费内容。
+{{- else -}}
+该用户不是 VIP，只推荐免费内容，可以适当介绍会员权益。
+{{- end}}
+
+请针对每个兴趣分别推荐：
+{{- range $i, $interest := .interests}}
+{{add $i 1}}. {{$interest}}
+{{- end}}`),
+	)
+
+	// Jinja2：同样的逻辑，使用内置过滤器
+	jinjaTemplate := prompt.FromMessages(
+		schema.Jinja2,
 		schema.SystemMessage("你是一个智能推荐助手"),
 		schema.UserMessage(`用户信息：
-姓名：{name}
-年龄：{age}
-兴趣：{interests}
-VIP等级：{vip_level}
+姓名：{{ name }}
+年龄：{{ age }}
+兴趣：{{ interests | join("、") }}
+{%- if bio %}
+简介：{{ bio | truncate(20, true, "…", 0) }}
+{%- endif %}
+{% if vip_level >= 3 -%}
+该用户是高级 VIP（{{ vip_level }} 级），优先推荐独家和付费内容。
+{%- elif vip_level > 0 -%}
+该用户是 VIP（{{ vip_level }} 级），可以推荐部分付费内容。
+{%- else -%}
+该用户不是 VIP，只推荐免费内容，可以适当介绍会员权益。
+{%- endif %}
 
-请根据以上信息推荐合适的内容。`),
+请针对每个兴趣分别推荐：
+{%- for interest in interests %}
+{{ loop.index }}. {{ interest }}
+{%- endfor %}`),
 	)
 
 	// 准备用户数据
-	user := UserProfile{
-		Name:      "张三",
-		Age:       28,
-		Interests: []string{"编程", "阅读", "旅行"},
-		VIPLevel:  3,
+	users := []UserProfile{
+		{
+			Name:      "张三",
+			Age:       28,
+			Interests: []string{"编程", "阅读", "旅行"},
+			VIPLevel:  3,
+			Bio:       "后端工程师，业余时间喜欢读历史类书籍，每年会安排两次长途旅行。",
+		},
+		{
+			Name:      "李四",
+			Age:       22,
+			Interests: []string{"摄影", "音乐"},
+		},
 	}
 
-	variables := map[string]any{
-		"name":      user.Name,
-		"age":       user.Age,
-		"interests": fmt.Sprintf("%v", user.Interests),
-		"vip_level": user.VIPLevel,
-	}
-
-	messages, err := template.Format(ctx, variables)
-	if err != nil {
-		log.Fatalf("格式化失败: %v", err)
-	}
+	for _, user := range users {
+		// 结构体直接绑定为模板变量，字段名转为 snake_case：VIPLevel -> vip_level
+		variables, err := prompts.Bind(user)
+		if err != nil {
+			log.Fatalf("绑定变量失败: %v", err)
+		}
 
-	for _, msg := range messages {
-		fmt.Printf("[%s]\n%s\n\n", msg.Role, msg.Content)
+		for _, t := range []struct {
+			name     string
+			template prompt.ChatTemplate
+		}{{"GoTemplate", goTemplate}, {"Jinja2", jinjaTemplate}} {
+			messages, err := t.template.Format(ctx, variables)
+			if err != nil {
+				log.Fatalf("%s 格式化失败: %v", t.name, err)
+			}
+			fmt.Printf("===== %s / %s =====\n", user.Name, t.name)
+			for _, msg := range messages {
+				fmt.Printf("[%s]\n%s\n\n", msg.Role, msg.Content)
+			}
+		}
 	}
 }
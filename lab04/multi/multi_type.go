@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/NuyoahCh/einotelos/einox/prompts"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
 )
@@ -13,8 +14,7 @@ func main() {
 	ctx := context.Background()
 
 	// 支持多种角色的消息
-	template := prompt.FromMessages(
-		schema.FString,
+	messagesTemplates := []schema.MessagesTemplate{
 		// System: 系统提示，定义 AI 的角色和行为
 		schema.SystemMessage("你是{role}，你的专长是{expertise}"),
 
@@ -26,7 +26,15 @@ func main() {
 
 		// User: 继续对话
 		schema.UserMessage("请详细说明"),
-	)
+	}
+	template := prompt.FromMessages(schema.FString, messagesTemplates...)
+
+	// 声明变量的类型与是否必需，Format 之前先做静态校验
+	vars := []prompts.Variable{
+		{Name: "role", Type: prompts.TypeString},
+		{Name: "expertise", Type: prompts.TypeString},
+		{Name: "question", Type: prompts.TypeString},
+	}
 
 	variables := map[string]any{
 		"role":      "一位了解哲学的程序员",
@@ -34,6 +42,22 @@ func main() {
 		"question":  "在不确定的环境中，如何做出最佳决策？",
 	}
 
+	report, err := prompts.Check(schema.FString, messagesTemplates, vars, variables)
+	if err != nil {
+		log.Fatalf("解析模板失败: %v", err)
+	}
+	if err := report.Err(); err != nil {
+		log.Fatalf("变量校验失败: %v", err)
+	}
+
+	// 漏传 expertise、多传 topic、question 类型不对时，校验会一次列出全部问题
+	report, _ = prompts.Check(schema.FString, messagesTemplates, vars, map[string]any{
+		"role":     "一位了解哲学的程序员",
+		"question": 42,
+		"topic":    "决策",
+	})
+	fmt.Printf("错误示例的校验结果: %s\n\n", report)
+
 	messages, err := template.Format(ctx, variables)
 	if err != nil {
 		log.Fatalf("格式化失败: %v", err)
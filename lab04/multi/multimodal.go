@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/NuyoahCh/einotelos/einox/media"
+	"github.com/NuyoahCh/einotelos/einox/prompts"
+	"github.com/cloudwego/eino-ext/components/model/deepseek"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+// main01 多模态消息：本地图片 / 文件转成消息片段，模板中使用多模态占位符，不支持视觉的模型直接报能力错误
+func main01() {
+	ctx := context.Background()
+
+	// 1) 直接构造：从路径读取图片与文件，按内容识别 MIME 类型并 base64 编码
+	chart, err := media.Image("./testdata/sales_chart.png")
+	if err != nil {
+		log.Fatalf("读取图片失败: %v", err)
+	}
+	table, err := media.File("./testdata/sales.csv")
+	if err != nil {
+		log.Fatalf("读取文件失败: %v", err)
+	}
+	msg := media.UserMessage("对比图表和表格中的数据是否一致", chart, table)
+	printParts(msg)
+
+	// 超过大小限制时返回 ErrTooLarge，不会把大文件整个读进内存
+	strict := &media.Loader{MaxImageSize: 100}
+	if _, err := strict.Image("./testdata/sales_chart.png"); errors.Is(err, media.ErrTooLarge) {
+		fmt.Printf("大小限制: %v\n\n", err)
+	}
+
+	// 2) 模板：文本照常渲染，chart 变量可以是图片地址、[]byte 或已构造好的片段；
+	// 变量值可能来自用户，读取本地路径必须用 MediaMessageIn 并把 Loader 限制在 BaseDir 内
+	testdata := &media.Loader{BaseDir: "./testdata"}
+	messagesTemplates := []schema.MessagesTemplate{
+		schema.SystemMessage("你是一名{role}"),
+		prompts.MediaMessageIn(testdata, "请分析这张{kind}，指出增长最快的季度", "chart"),
+	}
+	variables := map[string]any{
+		"role":  "数据分析师",
+		"kind":  "柱状图",
+		"chart": "sales_chart.png",
+	}
+	report, err := prompts.Check(schema.FString, messagesTemplates, nil, variables)
+	if err != nil {
+		log.Fatalf("解析模板失败: %v", err)
+	}
+	fmt.Printf("模板校验: %s\n", report)
+	chatTemplate := prompt.FromMessages(schema.FString, messagesTemplates...)
+	messages, err := chatTemplate.Format(ctx, variables)
+	if err != nil {
+		log.Fatalf("格式化失败: %v", err)
+	}
+	// 越出 BaseDir 的路径（包括经符号链接跳出的）直接报错
+	_, err = chatTemplate.Format(ctx, map[string]any{"role": "数据分析师", "kind": "柱状图", "chart": "../../go.mod"})
+	if errors.Is(err, media.ErrOutsideBaseDir) {
+		fmt.Printf("路径限制: %v\n", err)
+	}
+	for _, m := range messages {
+		printParts(m)
+	}
+
+	// 3) 能力检查：deepseek-chat 不支持图片输入，请求前直接返回 CapabilityError
+	deepseekModel, err := deepseek.NewChatModel(ctx, &deepseek.ChatModelConfig{
+		APIKey:  os.Getenv("DEEPSEEK_API_KEY"),
+		Model:   "deepseek-chat",
+		BaseURL: "https://api.deepseek.com",
+	})
+	if err != nil {
+		log.Fatalf("创建模型失败: %v", err)
+	}
+	chatModel, err := media.NewChatModel(deepseekModel, "deepseek-chat", nil)
+	if err != nil {
+		log.Fatalf("包装模型失败: %v", err)
+	}
+	_, err = chatModel.Generate(ctx, messages)
+	var capErr *media.CapabilityError
+	if errors.As(err, &capErr) {
+		fmt.Printf("能力检查: %v（errors.Is ErrUnsupportedModality = %v）\n", err, errors.Is(err, media.ErrUnsupportedModality))
+	}
+}
+
+func printParts(m *schema.Message) {
+	if len(m.UserInputMultiContent) == 0 {
+		fmt.Printf("[%s] %s\n", m.Role, m.Content)
+		return
+	}
+	fmt.Printf("[%s] %d 个片段\n", m.Role, len(m.UserInputMultiContent))
+	for _, p := range m.UserInputMultiContent {
+		switch {
+		case p.Image != nil:
+			fmt.Printf("  - image %s, base64 %d 字节\n", p.Image.MIMEType, len(*p.Image.Base64Data))
+		case p.File != nil:
+			fmt.Printf("  - file %s (%s), base64 %d 字节\n", p.File.Name, p.File.MIMEType, len(*p.File.Base64Data))
+		default:
+			fmt.Printf("  - text %s\n", p.Text)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/NuyoahCh/einotelos/einox/prompts"
+	"github.com/NuyoahCh/einotelos/einox/prompts/promptstest"
+)
+
+// TestPrompts 静态检查 prompts 目录下的全部模板，并用示例中的变量校验每个版本
+func TestPrompts(t *testing.T) {
+	promptstest.CheckDir(t, "prompts")
+
+	registry, err := prompts.NewRegistry(t.Context(), &prompts.Config{Dir: "prompts"})
+	if err != nil {
+		t.Fatalf("load prompts: %v", err)
+	}
+	values := map[string]map[string]any{
+		"translator": {
+			"source_lang": "中文",
+			"target_lang": "英文",
+			"text":        "Eino 是一个强大的 AI 开发框架",
+		},
+		"code_reviewer": {
+			"language": "Go",
+			"code":     "func add(a, b int) int { return a + b }",
+		},
+		"tech_interviewer": {
+			"position": "Go后端开发",
+			"level":    "中级",
+			"answer":   "goroutine 是 Go 语言的轻量级线程",
+		},
+	}
+	for _, tpl := range registry.List() {
+		v, ok := values[tpl.Name]
+		if !ok {
+			t.Errorf("prompt %s: no test values", tpl.Ref())
+			continue
+		}
+		promptstest.CheckTemplate(t, tpl, v)
+	}
+}
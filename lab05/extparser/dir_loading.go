@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/NuyoahCh/einotelos/einox/loader"
+)
+
+// main01 递归加载目录：按 include/exclude 过滤，每个文件交给扩展解析器，单个文件失败不影响整批
+func main01() {
+	ctx := context.Background()
+
+	extParser, err := newExtParser(ctx)
+	if err != nil {
+		log.Fatalf("创建扩展解析器失败: %v", err)
+	}
+
+	dirLoader, err := loader.NewDirLoader(&loader.DirConfig{
+		Parser:      extParser,
+		Include:     []string{"*.{html,htm,md,txt,pdf,docx}"},
+		Exclude:     []string{"build"}, // 匹配到的目录整个跳过
+		MaxFileSize: 1 << 20,
+		Concurrency: 4,
+		// 默认跳过隐藏文件与符号链接
+	})
+	if err != nil {
+		log.Fatalf("创建目录加载器失败: %v", err)
+	}
+
+	res, err := dirLoader.LoadDir(ctx, "./testdata/docs")
+	if err != nil {
+		log.Fatalf("加载目录失败: %v", err)
+	}
+
+	fmt.Printf("扫描到 %d 个文件，得到 %d 个文档\n", res.Files, len(res.Docs))
+	for _, doc := range res.Docs {
+		fmt.Printf("  %-32s %-6s %4d 字符\n", doc.ID, doc.MetaData[loader.MetaKeyExtension], len([]rune(doc.Content)))
+	}
+	fmt.Printf("\n跳过 %d 个：\n", len(res.Skipped))
+	for _, s := range res.Skipped {
+		fmt.Printf("  %s（%s）\n", s.Path, s.Reason)
+	}
+	fmt.Printf("\n失败 %d 个：\n", len(res.Errors))
+	for _, fe := range res.Errors {
+		fmt.Printf("  %v\n", fe)
+	}
+}
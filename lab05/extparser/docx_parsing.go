@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/NuyoahCh/einotelos/einox/parsers"
+	"github.com/cloudwego/eino/components/document/parser"
+)
+
+// main02 解析 Word 文档：标题、列表、表格保留为 Markdown 结构，文档属性进入 MetaData
+func main02() {
+	ctx := context.Background()
+
+	extParser, err := newExtParser(ctx)
+	if err != nil {
+		log.Fatalf("创建扩展解析器失败: %v", err)
+	}
+
+	path := "./testdata/docs/guide/chain_guide.docx"
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("打开文件失败: %v", err)
+	}
+	defer file.Close()
+
+	docs, err := extParser.Parse(ctx, file, parser.WithURI(path))
+	if err != nil {
+		log.Fatalf("解析文件失败: %v", err)
+	}
+
+	doc := docs[0]
+	fmt.Println("文档属性:")
+	for _, key := range []string{parsers.MetaKeyTitle, parsers.MetaKeyAuthor, parsers.MetaKeyKeywords, parsers.MetaKeyCreated, parsers.MetaKeyModified} {
+		fmt.Printf("  %-10s %v\n", key, doc.MetaData[key])
+	}
+	fmt.Printf("\n内容:\n%s\n", doc.Content)
+}
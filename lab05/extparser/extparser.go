@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 
+	"github.com/NuyoahCh/einotelos/einox/parsers"
 	htmlparser "github.com/cloudwego/eino-ext/components/document/parser/html"
 	pdfparser "github.com/cloudwego/eino-ext/components/document/parser/pdf"
 	"github.com/cloudwego/eino/components/document/parser"
@@ -15,30 +16,8 @@ func main() {
 	// 创建上下文
 	ctx := context.Background()
 
-	// 创建各个解析器
-	// HTML 解析器：使用 Config（不是 ParserConfig）
-	htmlParser, err := htmlparser.NewParser(ctx, &htmlparser.Config{})
-	if err != nil {
-		log.Fatalf("创建 HTML 解析器失败: %v", err)
-	}
-
-	// PDF 解析器：使用 NewPDFParser 和 Config（不是 NewParser 和 ParserConfig）
-	pdfParser, err := pdfparser.NewPDFParser(ctx, &pdfparser.Config{
-		ToPages: false, // 合并所有页面为一个文档
-	})
-	if err != nil {
-		log.Fatalf("创建 PDF 解析器失败: %v", err)
-	}
-
 	// 创建扩展解析器，注册不同扩展名的解析器
-	extParser, err := parser.NewExtParser(ctx, &parser.ExtParserConfig{
-		Parsers: map[string]parser.Parser{
-			".html": htmlParser, // HTML 文件使用 HTML 解析器
-			".htm":  htmlParser, // HTM 文件也使用 HTML 解析器
-			".pdf":  pdfParser,  // PDF 文件使用 PDF 解析器
-		},
-		// FallbackParser 是可选的，如果不提供，默认使用 TextParser
-	})
+	extParser, err := newExtParser(ctx)
 	if err != nil {
 		log.Fatalf("创建扩展解析器失败: %v", err)
 	}
@@ -73,3 +52,59 @@ func main() {
 		}
 	}
 }
+
+// newExtParser 创建扩展解析器，目录加载示例也复用这里的注册表
+func newExtParser(ctx context.Context) (*parser.ExtParser, error) {
+	// 创建各个解析器
+	// HTML 解析器：使用 Config（不是 ParserConfig）
+	htmlParser, err := htmlparser.NewParser(ctx, &htmlparser.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("创建 HTML 解析器失败: %w", err)
+	}
+
+	// PDF 解析器：使用 NewPDFParser 和 Config（不是 NewParser 和 ParserConfig）
+	pdfParser, err := pdfparser.NewPDFParser(ctx, &pdfparser.Config{
+		ToPages: false, // 合并所有页面为一个文档
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建 PDF 解析器失败: %w", err)
+	}
+
+	// DOCX 解析器：纯 Go 实现，标题、列表、表格转为 Markdown，文档属性写入 MetaData
+	docxParser, err := parsers.NewDocxParser(ctx, &parsers.DocxConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("创建 DOCX 解析器失败: %w", err)
+	}
+
+	// 表格解析器：默认每行一个文档，自动识别表头并推断列类型
+	csvParser, err := parsers.NewCSVParser(ctx, &parsers.CSVConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("创建 CSV 解析器失败: %w", err)
+	}
+	xlsxParser, err := parsers.NewXLSXParser(ctx, &parsers.XLSXConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("创建 XLSX 解析器失败: %w", err)
+	}
+
+	// Markdown 解析器：front matter 进入 MetaData，记录标题大纲与代码块语言
+	mdParser, err := parsers.NewMarkdownParser(ctx, &parsers.MarkdownConfig{})
+	if err != nil {
+		return nil, fmt.Errorf("创建 Markdown 解析器失败: %w", err)
+	}
+
+	// 扩展解析器：按扩展名分发到对应的解析器
+	return parser.NewExtParser(ctx, &parser.ExtParserConfig{
+		Parsers: map[string]parser.Parser{
+			".html":     htmlParser, // HTML 文件使用 HTML 解析器
+			".htm":      htmlParser, // HTM 文件也使用 HTML 解析器
+			".pdf":      pdfParser,  // PDF 文件使用 PDF 解析器
+			".docx":     docxParser, // Word 文档使用 DOCX 解析器
+			".csv":      csvParser,  // CSV / TSV 按行输出文档
+			".tsv":      csvParser,
+			".xlsx":     xlsxParser, // Excel 工作簿按行输出文档
+			".md":       mdParser,   // Markdown 使用 Markdown 解析器
+			".markdown": mdParser,
+		},
+		// FallbackParser 是可选的，如果不提供，默认使用 TextParser
+	})
+}
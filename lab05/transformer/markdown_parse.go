@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/NuyoahCh/einotelos/einox/parsers"
+	markdown "github.com/cloudwego/eino-ext/components/document/transformer/splitter/markdown"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// main03 Markdown 解析：front matter 进入 MetaData，记录标题路径与代码块语言，再按标题切分
+func main03() {
+	ctx := context.Background()
+	path := "./testdata/eino_guide.md"
+
+	// 方式一：整篇解析后交给 HeaderSplitter，front matter 已从正文移除，随 MetaData 复制到每个切片
+	mdParser, err := parsers.NewMarkdownParser(ctx, &parsers.MarkdownConfig{})
+	if err != nil {
+		log.Fatalf("创建 Markdown 解析器失败: %v", err)
+	}
+	docs := parseMarkdown(ctx, mdParser, path)
+	doc := docs[0]
+	fmt.Printf("标题: %v，作者: %v，标签: %v\n", doc.MetaData[parsers.MetaKeyTitle], doc.MetaData["author"], doc.MetaData["tags"])
+	fmt.Println("大纲:")
+	for _, h := range doc.MetaData[parsers.MetaKeyOutline].([]parsers.Heading) {
+		fmt.Printf("  %s%s\n", strings.Repeat("  ", h.Level-1), h.Text)
+	}
+	fmt.Println("代码块:")
+	for _, c := range doc.MetaData[parsers.MetaKeyCodeBlocks].([]parsers.CodeBlock) {
+		fmt.Printf("  第 %d 行 %-5q %d 行，位于 %s\n", c.Line, c.Language, c.Lines, strings.Join(c.Path, " > "))
+	}
+
+	splitter, err := markdown.NewHeaderSplitter(ctx, &markdown.HeaderConfig{
+		Headers: map[string]string{"#": "h1", "##": "h2"},
+	})
+	if err != nil {
+		log.Fatalf("创建分割器失败: %v", err)
+	}
+	chunks, err := splitter.Transform(ctx, docs)
+	if err != nil {
+		log.Fatalf("分割文档失败: %v", err)
+	}
+	fmt.Printf("\n===== HeaderSplitter：%d 块 =====\n", len(chunks))
+	for i, c := range chunks {
+		fmt.Printf("块 %d  h1=%v h2=%v title=%v\n", i+1, c.MetaData["h1"], c.MetaData["h2"], c.MetaData[parsers.MetaKeyTitle])
+	}
+
+	// 方式二：解析器直接按标题切分，能识别 Setext 标题，并只保留本节的代码块
+	sectionParser, err := parsers.NewMarkdownParser(ctx, &parsers.MarkdownConfig{SplitSections: true})
+	if err != nil {
+		log.Fatalf("创建 Markdown 解析器失败: %v", err)
+	}
+	sections := parseMarkdown(ctx, sectionParser, path)
+	fmt.Printf("\n===== 按小节解析：%d 块 =====\n", len(sections))
+	for i, s := range sections {
+		var langs []string
+		for _, c := range s.MetaData[parsers.MetaKeyCodeBlocks].([]parsers.CodeBlock) {
+			langs = append(langs, fmt.Sprintf("%q", c.Language))
+		}
+		fmt.Printf("块 %d  路径=%v 代码=%v\n", i+1, s.MetaData[parsers.MetaKeyHeadingPath], langs)
+	}
+}
+
+func parseMarkdown(ctx context.Context, p parser.Parser, path string) []*schema.Document {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("打开文件失败: %v", err)
+	}
+	defer file.Close()
+	docs, err := p.Parse(ctx, file, parser.WithURI(path))
+	if err != nil {
+		log.Fatalf("解析文件失败: %v", err)
+	}
+	return docs
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/NuyoahCh/einotelos/einox/parsers"
+	"github.com/cloudwego/eino/components/document/parser"
+	"github.com/cloudwego/eino/schema"
+)
+
+// main01 表格知识库：FAQ 表格与商品目录按行切成文档，内容列参与向量化，其余列作为 MetaData 过滤条件
+func main01() {
+	ctx := context.Background()
+
+	// FAQ：每行一个文档，问题与答案拼成内容，分类、浏览量进入 MetaData
+	csvParser, err := parsers.NewCSVParser(ctx, &parsers.CSVConfig{
+		TableConfig: parsers.TableConfig{
+			Mode:           parsers.TableModeRow,
+			ContentColumns: []string{"问题", "答案"},
+		},
+	})
+	if err != nil {
+		log.Fatalf("创建 CSV 解析器失败: %v", err)
+	}
+	faqDocs := parseFile(ctx, csvParser, "./testdata/faq.csv")
+
+	// 商品目录：只解析“商品”工作表，名称与描述作为内容，价格、上架日期、是否在售按类型写入 MetaData
+	rowParser, err := parsers.NewXLSXParser(ctx, &parsers.XLSXConfig{
+		TableConfig: parsers.TableConfig{
+			Mode:           parsers.TableModeRow,
+			ContentColumns: []string{"名称", "描述"},
+		},
+		Sheets: []string{"商品"},
+	})
+	if err != nil {
+		log.Fatalf("创建 XLSX 解析器失败: %v", err)
+	}
+	catalogDocs := parseFile(ctx, rowParser, "./testdata/catalog.xlsx")
+
+	// 整表模式：每个工作表渲染为一个 Markdown 表格，适合需要整体对比的场景
+	sheetParser, err := parsers.NewXLSXParser(ctx, &parsers.XLSXConfig{
+		TableConfig: parsers.TableConfig{Mode: parsers.TableModeSheet},
+	})
+	if err != nil {
+		log.Fatalf("创建 XLSX 解析器失败: %v", err)
+	}
+	sheetDocs := parseFile(ctx, sheetParser, "./testdata/catalog.xlsx")
+
+	fmt.Println("===== FAQ（按行） =====")
+	printDocs(faqDocs)
+	fmt.Println("===== 商品目录（按行） =====")
+	printDocs(catalogDocs)
+	fmt.Println("===== 商品目录（按工作表） =====")
+	for _, doc := range sheetDocs {
+		fmt.Printf("%s\n列类型: %v\n\n", doc.Content, doc.MetaData[parsers.MetaKeyColumnTypes])
+	}
+
+	// 配置了向量模型时接入文档问答，表格文档和普通文档走同一条向量化流程
+	if os.Getenv("ARK_API_KEY") == "" {
+		fmt.Println("未设置 ARK_API_KEY，跳过向量化问答")
+		return
+	}
+	qa, err := NewDocumentQA(
+		os.Getenv("ARK_API_KEY"),
+		os.Getenv("ARK_EMBEDDING_MODEL"),
+		os.Getenv("DEEPSEEK_API_KEY"),
+	)
+	if err != nil {
+		log.Fatalf("创建问答系统失败: %v", err)
+	}
+	if err := qa.LoadDocuments(ctx, append(faqDocs, catalogDocs...)); err != nil {
+		log.Fatalf("加载文档失败: %v", err)
+	}
+	answer, err := qa.Query(ctx, "有没有讲检索增强生成的课程？多少钱？")
+	if err != nil {
+		log.Fatalf("查询失败: %v", err)
+	}
+	fmt.Printf("回答: %s\n", answer)
+}
+
+func parseFile(ctx context.Context, p parser.Parser, path string) []*schema.Document {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("打开文件失败: %v", err)
+	}
+	defer file.Close()
+	docs, err := p.Parse(ctx, file, parser.WithURI(path))
+	if err != nil {
+		log.Fatalf("解析 %s 失败: %v", path, err)
+	}
+	return docs
+}
+
+func printDocs(docs []*schema.Document) {
+	for _, doc := range docs {
+		fmt.Printf("[第 %v 行] %s\n", doc.MetaData[parsers.MetaKeyRow], doc.Content)
+		keys := make([]string, 0, len(doc.MetaData))
+		for k := range doc.MetaData {
+			if !strings.HasPrefix(k, "_") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Printf("    %s = %v (%T)\n", k, doc.MetaData[k], doc.MetaData[k])
+		}
+	}
+	fmt.Println()
+}
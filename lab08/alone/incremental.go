@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/NuyoahCh/einotelos/einox/ingest"
+	"github.com/NuyoahCh/einotelos/einox/loader"
+	fileloader "github.com/cloudwego/eino-ext/components/document/loader/file"
+	"github.com/cloudwego/eino-ext/components/document/transformer/splitter/recursive"
+	einoIndexer "github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// vectorStore 内存向量库：实现 eino 的 Indexer 与 ingest.Deleter，记录向量化次数
+// 换成 VikingDB 时，Deleter 可以用 ingest.DeleteFunc 包装 collection.DeleteData
+type vectorStore struct {
+	embedder Embedder
+	docs     map[string]*schema.Document
+	embedded int
+}
+
+func (s *vectorStore) Store(ctx context.Context, docs []*schema.Document, opts ...einoIndexer.Option) ([]string, error) {
+	texts := make([]string, len(docs))
+	for i, d := range docs {
+		texts[i] = d.Content
+	}
+	if _, err := s.embedder.EmbedStrings(ctx, texts); err != nil {
+		return nil, err
+	}
+	s.embedded += len(texts)
+	ids := make([]string, len(docs))
+	for i, d := range docs {
+		s.docs[d.ID] = d
+		ids[i] = d.ID
+	}
+	return ids, nil
+}
+
+func (s *vectorStore) Delete(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		delete(s.docs, id)
+	}
+	return nil
+}
+
+// main01 增量入库：加载 → 切分 → 向量化写入，清单记录每个文件的哈希与切片 ID，重复运行只处理变化的文件
+func main01() {
+	ctx := context.Background()
+
+	// 在临时目录里操作知识库副本，演示修改 / 删除 / 新增文件
+	work, err := os.MkdirTemp("", "ingest-*")
+	if err != nil {
+		log.Fatalf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(work)
+	kbDir := filepath.Join(work, "kb")
+	if err := os.CopyFS(kbDir, os.DirFS("./testdata/kb")); err != nil {
+		log.Fatalf("复制知识库失败: %v", err)
+	}
+
+	dirLoader, err := loader.NewDirLoader(&loader.DirConfig{Include: []string{"*.md"}})
+	if err != nil {
+		log.Fatalf("创建目录加载器失败: %v", err)
+	}
+	fileLoader, err := fileloader.NewFileLoader(ctx, &fileloader.FileLoaderConfig{})
+	if err != nil {
+		log.Fatalf("创建文件加载器失败: %v", err)
+	}
+	splitter, err := recursive.NewSplitter(ctx, &recursive.Config{ChunkSize: 60, OverlapSize: 10})
+	if err != nil {
+		log.Fatalf("创建分割器失败: %v", err)
+	}
+	store := &vectorStore{embedder: &FakeEmbedder{}, docs: map[string]*schema.Document{}}
+
+	newIngester := func(model string) *ingest.Ingester {
+		ing, err := ingest.New(&ingest.Config{
+			ManifestPath:   filepath.Join(work, "manifest.json"),
+			Loader:         fileLoader,
+			Transformer:    splitter,
+			Indexer:        store,
+			Deleter:        store,
+			EmbeddingModel: model,
+			Pipeline:       "recursive-60-10",
+		})
+		if err != nil {
+			log.Fatalf("创建增量入库失败: %v", err)
+		}
+		return ing
+	}
+	run := func(title string, ing *ingest.Ingester) {
+		uris, err := dirLoader.List(ctx, kbDir)
+		if err != nil {
+			log.Fatalf("列出文件失败: %v", err)
+		}
+		before := store.embedded
+		res, err := ing.Run(ctx, uris)
+		if err != nil {
+			log.Fatalf("入库失败: %v", err)
+		}
+		fmt.Printf("===== %s =====\n%s\n", title, res)
+		for _, c := range res.Changes {
+			if c.Action != ingest.ActionSkip {
+				fmt.Printf("  %-6s %-14s %s\n", c.Action, filepath.Base(c.URI), c.Reason)
+			}
+		}
+		for _, fe := range res.Errors {
+			fmt.Printf("  失败: %v\n", fe)
+		}
+		fmt.Printf("本次向量化 %d 个切片，库中共 %d 个切片\n\n", store.embedded-before, len(store.docs))
+	}
+
+	ing := newIngester("fake-embedding-v1")
+	run("首次入库", ing)
+	run("再次运行（无变化）", ing)
+
+	// 修改一个、删除一个、新增一个
+	if err := os.WriteFile(filepath.Join(kbDir, "retriever.md"),
+		[]byte("# Retriever\n\nRetriever 根据查询召回相关文档。\n"), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(kbDir, "embedding.md")); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(kbDir, "lambda.md"),
+		[]byte("# Lambda\n\nLambda 把普通函数包装成可编排的节点，支持 Invoke、Stream、Collect、Transform 四种模式。\n"), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	run("修改 / 删除 / 新增之后", ing)
+
+	// 换了向量模型，旧向量不能和新向量混用，全部重建
+	run("更换向量模型", newIngester("fake-embedding-v2"))
+}
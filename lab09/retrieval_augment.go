@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/NuyoahCh/einotelos/einox/guard"
 	"github.com/cloudwego/eino/callbacks"
 	einoRetriever "github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
@@ -24,6 +25,18 @@ type RagPack struct {
 	Docs          []*schema.Document
 	Context       string // 拼好的证据上下文
 	HasKnowledge  bool
+	System        string // system prompt；文档经过 Guard 时已附带 guard.FenceInstruction
+}
+
+// ragSystemPrompt 问答用的 system prompt，证据上下文拼在其后
+const ragSystemPrompt = "你是一个知识库问答助手。优先根据下面的证据回答用户问题；证据不足时如实说明，不要编造。"
+
+// Messages 组装成可直接交给 ChatModel 的消息：system（规则 + 证据）+ 用户原始问题
+func (p *RagPack) Messages() []*schema.Message {
+	return []*schema.Message{
+		schema.SystemMessage(p.System + "\n\n" + p.Context),
+		schema.UserMessage(p.OriginalQuery),
+	}
 }
 
 func main() {
@@ -68,17 +81,38 @@ func main() {
 	}
 	cb := callbacksHelper.NewHandlerHelper().Retriever(handler).Handler()
 
+	// 检索回来的文档同样不可信：一律加分隔符，命中注入特征的片段先清洗，分数过高的直接丢弃
+	docGuard, err := guard.New(&guard.Config{Action: guard.ActionSanitize, AlwaysFence: true})
+	if err != nil {
+		panic(err)
+	}
+	guardCb := callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if d, ok := output.(*guard.Decision); ok && info.Component == guard.ComponentOfGuard && d.Action != guard.ActionFence {
+				log.Printf("[Guard] source=%s score=%.2f action=%s findings=%d", d.Source, d.Score, d.Action, len(d.Findings))
+			}
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+			if info.Component == guard.ComponentOfGuard {
+				log.Printf("[Guard] %v", err)
+			}
+			return ctx
+		}).
+		Build()
+
 	// 5) 运行一次“检索增强”
 	userQuery := "狗的常见品种有哪些？我想养一只适合新手的"
 	pack, err := RetrieveAugment(
 		ctx,
 		ret,
-		cb,
+		[]callbacks.Handler{cb, guardCb},
 		userQuery,
 		RetrievePolicy{
 			TopK:           5,
 			ScoreThreshold: 0.2,
 			SubIndex:       "pet_kb", // 你有做子索引隔离的话就用；没有就留空
+			Guard:          docGuard,
 		},
 	)
 	if err != nil {
@@ -89,6 +123,7 @@ func main() {
 	fmt.Println("original:", pack.OriginalQuery)
 	fmt.Println("rewrite  :", pack.RewriteQuery)
 	fmt.Println("has_kb   :", pack.HasKnowledge)
+	fmt.Println("system   :", pack.System)
 	fmt.Println("context  :\n", pack.Context)
 }
 
@@ -99,17 +134,20 @@ type RetrievePolicy struct {
 	Index          string
 	SubIndex       string
 	Timeout        time.Duration
+	// Guard 可选：文档进入上下文前做注入检查，RagPack.System 会随之加上 guard.FenceInstruction
+	Guard *guard.Guard
 }
 
 // RetrieveAugment：
 // 1) rewrite query（让检索更稳）
 // 2) 调用 Retriever.Retrieve（用公共 Options 控制 TopK/阈值/子索引/embedding 等）:contentReference[oaicite:4]{index=4}
 // 3) 空召回兜底（防止硬塞“最像但不相关”的片段）
-// 4) 组装 context（可直接进 prompt）
+// 4) 注入检查（可选）：丢弃被拒绝的文档，其余隔离/清洗
+// 5) 组装 context 与 system prompt（可直接进 prompt）
 func RetrieveAugment(
 	ctx context.Context,
 	ret einoRetriever.Retriever,
-	cb callbacks.Handler,
+	handlers []callbacks.Handler,
 	query string,
 	p RetrievePolicy,
 ) (*RagPack, error) {
@@ -119,6 +157,8 @@ func RetrieveAugment(
 		defer cancel()
 	}
 
+	ctx = callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "RetrieveAugment"}, handlers...)
+
 	rewrite := RewriteQuery(query)
 
 	// 公共 Option：TopK / ScoreThreshold / Index / SubIndex / Embedding 等 :contentReference[oaicite:6]{index=6}
@@ -140,6 +180,14 @@ func RetrieveAugment(
 	if err != nil {
 		return nil, err
 	}
+	system := ragSystemPrompt
+	if p.Guard != nil {
+		if docs, err = p.Guard.Documents(ctx, docs); err != nil {
+			return nil, err
+		}
+		// 文档被加上分隔符后，要告诉模型分隔符内只是数据
+		system += "\n" + guard.FenceInstruction
+	}
 
 	// 空召回兜底：没有命中就不要硬拼“伪证据”
 	hasKB := len(docs) > 0
@@ -154,6 +202,7 @@ func RetrieveAugment(
 		Docs:          docs,
 		Context:       context,
 		HasKnowledge:  hasKB,
+		System:        system,
 	}, nil
 }
 